@@ -0,0 +1,23 @@
+// Package textnorm holds the accent removal shared by strings, its subpackages and utime,
+// which cannot import strings.
+package textnorm
+
+import (
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NewAccentRemover return a transformer decomposing the text, dropping the combining marks and
+// composing it back. Transformers keep state, so each user needs its own.
+func NewAccentRemover() transform.Transformer {
+	return transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+}
+
+// RemoveAccents return str without accents.
+func RemoveAccents(str string) string {
+	removed, _, _ := transform.String(NewAccentRemover(), str)
+	return removed
+}
@@ -0,0 +1,203 @@
+package address
+
+import (
+	"strings"
+	"unicode"
+
+	"golibs/cmd/internal/textnorm"
+)
+
+// Address holds the components of a postal address. StreetType and the unit
+// markers are stored in their canonical form, see the dictionary.
+type Address struct {
+	StreetType string
+	StreetName string
+	Number     string
+	Unit       string
+	PostalCode string
+	Locality   string
+}
+
+// String return the address components separated by spaces.
+func (a Address) String() string {
+	var parts []string
+	for _, part := range []string{a.StreetType, a.StreetName, a.Number, a.Unit, a.PostalCode, a.Locality} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Parse splits a Spanish ("Calle 5 #12-3, piso 2, 28001 Madrid") or English
+// ("123 Main St Apt 4, Springfield 10001") address into its components.
+func Parse(address string) Address {
+	var a Address
+
+	tokens, breaks := tokenize(address)
+	used := make([]bool, len(tokens))
+
+	// postal code, the last standalone code of five digits
+	for i := len(tokens) - 1; i > 0; i-- {
+		if isPostalCode(tokens[i]) && !numberMarkers[tokens[i-1]] {
+			a.PostalCode = tokens[i]
+			used[i] = true
+			break
+		}
+	}
+
+	// unit, every marker followed by its value
+	var unit []string
+	for i := 0; i < len(tokens)-1; i++ {
+		marker, ok := unitMarkers[tokens[i]]
+		if !ok || i == 0 {
+			continue
+		}
+		j := i + 1
+		if numberMarkers[tokens[j]] && j+1 < len(tokens) {
+			j++
+		}
+		if used[j] || ambiguousMarkers[tokens[i]] && !isUnitValue(tokens[j]) {
+			continue
+		}
+		unit = append(unit, marker, tokens[j])
+		for k := i; k <= j; k++ {
+			used[k] = true
+		}
+		i = j
+	}
+	a.Unit = strings.Join(unit, " ")
+
+	var rest []string
+	var restBreaks []bool
+	for i, token := range tokens {
+		if !used[i] {
+			rest = append(rest, token)
+			restBreaks = append(restBreaks, breaks[i])
+		}
+	}
+
+	if len(rest) == 0 {
+		return a
+	}
+
+	// English layout, the number goes first and the type after the name
+	if isNumber(rest[0]) && len(rest) > 1 {
+		a.Number = rest[0]
+		rest, restBreaks = rest[1:], restBreaks[1:]
+		// the type can also go first, as in Avenue of the Americas, and then the name ends
+		// at the next comma
+		if streetType, ok := streetTypes[rest[0]]; ok && len(rest) > 1 {
+			end := 2
+			for end < len(rest) && !restBreaks[end] {
+				end++
+			}
+			a.StreetType = streetType
+			a.StreetName = strings.Join(rest[1:end], " ")
+			a.Locality = strings.Join(rest[end:], " ")
+			return a
+		}
+		for i := 1; i < len(rest); i++ {
+			if streetType, ok := streetTypes[rest[i]]; ok {
+				a.StreetType = streetType
+				a.Locality = strings.Join(rest[i+1:], " ")
+				rest = rest[:i]
+				break
+			}
+		}
+		a.StreetName = strings.Join(rest, " ")
+		return a
+	}
+
+	if streetType, ok := streetTypes[rest[0]]; ok && len(rest) > 1 {
+		a.StreetType = streetType
+		rest = rest[1:]
+	}
+
+	var name, locality []string
+	for i := 0; i < len(rest); i++ {
+		token := rest[i]
+		switch {
+		case a.Number != "":
+			locality = append(locality, token)
+		case numberMarkers[token] && i+1 < len(rest):
+			a.Number = rest[i+1]
+			i++
+		case a.Number == "" && isNumber(token) && len(name) > 0:
+			a.Number = token
+		case a.StreetType == "" && len(name) > 0 && streetTypes[token] != "":
+			a.StreetType = streetTypes[token]
+		default:
+			name = append(name, token)
+		}
+	}
+	a.StreetName = strings.Join(name, " ")
+	a.Locality = strings.Join(locality, " ")
+
+	return a
+}
+
+// tokenize lowercase the address, removes the accents and punctuation, and split it in words
+// keeping the number markers apart from the number. breaks tells the words after a comma.
+func tokenize(address string) (tokens []string, breaks []bool) {
+	address = strings.ToLower(textnorm.RemoveAccents(address))
+
+	address = strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ';', ':', '/', '(', ')':
+			return ' '
+		}
+		return r
+	}, address)
+	address = strings.ReplaceAll(address, "#", " # ")
+	address = strings.ReplaceAll(address, ",", " , ")
+
+	afterComma := false
+	for _, token := range strings.Fields(address) {
+		if token == "," {
+			afterComma = true
+			continue
+		}
+		for _, marker := range []string{"nº", "n°", "no°"} {
+			if strings.HasPrefix(token, marker) && len(token) > len(marker) {
+				tokens = append(tokens, marker)
+				breaks = append(breaks, afterComma)
+				token, afterComma = token[len(marker):], false
+				break
+			}
+		}
+		tokens = append(tokens, token)
+		breaks = append(breaks, afterComma)
+		afterComma = false
+	}
+
+	return tokens, breaks
+}
+
+// isNumber report if token looks like a street number, such as 12, 12b or 12-3.
+func isNumber(token string) bool {
+	return token != "" && unicode.IsDigit(rune(token[0]))
+}
+
+// isUnitValue report if token looks like the value of a unit, a number or a single letter
+// such as 3, 4b or b.
+func isUnitValue(token string) bool {
+	return isNumber(token) || len(token) == 1 && unicode.IsLetter(rune(token[0]))
+}
+
+func isPostalCode(token string) bool {
+	code, extension, found := strings.Cut(token, "-")
+	if len(code) != 5 || !isDigits(code) {
+		return false
+	}
+	return !found || (len(extension) == 4 && isDigits(extension))
+}
+
+func isDigits(token string) bool {
+	for _, r := range token {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,162 @@
+package address_test
+
+import (
+	"golibs/cmd/strings/address"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    address.Address
+	}{
+		{
+			name:    "Spanish with hash",
+			address: "Calle 5 #12-3",
+			want: address.Address{
+				StreetType: "street",
+				StreetName: "5",
+				Number:     "12-3",
+			},
+		},
+		{
+			name:    "Spanish abbreviated",
+			address: "C/ 5 nº 12-3",
+			want: address.Address{
+				StreetType: "street",
+				StreetName: "5",
+				Number:     "12-3",
+			},
+		},
+		{
+			name:    "Spanish with unit",
+			address: "Avenida Libertador Nº1234 Piso 3",
+			want: address.Address{
+				StreetType: "avenue",
+				StreetName: "libertador",
+				Number:     "1234",
+				Unit:       "floor 3",
+			},
+		},
+		{
+			name:    "Spanish with postal code",
+			address: "Avenida de la Constitución 25, 28001 Madrid",
+			want: address.Address{
+				StreetType: "avenue",
+				StreetName: "de la constitucion",
+				Number:     "25",
+				PostalCode: "28001",
+				Locality:   "madrid",
+			},
+		},
+		{
+			name:    "English",
+			address: "123 Main St Apt #4, Springfield 10001",
+			want: address.Address{
+				StreetType: "street",
+				StreetName: "main",
+				Number:     "123",
+				Unit:       "apartment 4",
+				PostalCode: "10001",
+				Locality:   "springfield",
+			},
+		},
+		{
+			name:    "English with the type first",
+			address: "1200 Avenue of the Americas, New York 10020",
+			want: address.Address{
+				StreetType: "avenue",
+				StreetName: "of the americas",
+				Number:     "1200",
+				PostalCode: "10020",
+				Locality:   "new york",
+			},
+		},
+		{
+			name:    "Ambiguous unit marker",
+			address: "Calle Mayor 5, Esc. B, Of 301, 28013 Madrid",
+			want: address.Address{
+				StreetType: "street",
+				StreetName: "mayor",
+				Number:     "5",
+				Unit:       "stairs b office 301",
+				PostalCode: "28013",
+				Locality:   "madrid",
+			},
+		},
+		{
+			name:    "Empty",
+			address: "",
+			want:    address.Address{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := address.Parse(tt.address); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSimilarity(t *testing.T) {
+	type args struct {
+		source string
+		target string
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "Abbreviations",
+			args: args{
+				source: "Calle 5 #12-3",
+				target: "C/ 5 nº 12-3",
+			},
+			want: 1,
+		},
+		{
+			name: "Abbreviations with unit",
+			args: args{
+				source: "Av. Libertador 1234, piso 3",
+				target: "Avenida Libertador Nº1234 Piso 3",
+			},
+			want: 1,
+		},
+		{
+			name: "Missing components",
+			args: args{
+				source: "123 Main St Apt #4, Springfield 10001",
+				target: "123 Main Street, Apartment 4",
+			},
+			want: 1,
+		},
+		{
+			name: "Different number",
+			args: args{
+				source: "123 Main St",
+				target: "125 Main St",
+			},
+			want: 0.880952380952381,
+		},
+		{
+			name: "Different street",
+			args: args{
+				source: "Calle 5 #12-3",
+				target: "Carrera 7 #45-10",
+			},
+			want: 0.07142857142857141,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := address.GetSimilarity(tt.args.source, tt.args.target); got.Score != tt.want {
+				t.Errorf("GetSimilarity() = %+v, want %v", got, tt.want)
+			}
+		})
+	}
+}
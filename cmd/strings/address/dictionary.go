@@ -0,0 +1,126 @@
+package address
+
+// streetTypes maps the Spanish and English street types and their abbreviations
+// to a canonical name, so "Av." and "Avenue" are compared as the same type.
+var streetTypes = map[string]string{
+	// Spanish
+	"avenida":      "avenue",
+	"avda":         "avenue",
+	"av":           "avenue",
+	"calle":        "street",
+	"c":            "street",
+	"cl":           "street",
+	"cll":          "street",
+	"carrera":      "carrera",
+	"cra":          "carrera",
+	"kr":           "carrera",
+	"cr":           "carrera",
+	"carretera":    "highway",
+	"ctra":         "highway",
+	"autopista":    "highway",
+	"paseo":        "promenade",
+	"po":           "promenade",
+	"pso":          "promenade",
+	"plaza":        "square",
+	"pza":          "square",
+	"pl":           "square",
+	"callejon":     "alley",
+	"cjon":         "alley",
+	"camino":       "road",
+	"cno":          "road",
+	"bulevar":      "boulevard",
+	"diagonal":     "diagonal",
+	"dg":           "diagonal",
+	"transversal":  "transversal",
+	"tv":           "transversal",
+	"pasaje":       "passage",
+	"psje":         "passage",
+	"ronda":        "ring",
+	"rda":          "ring",
+	"glorieta":     "circle",
+	"urbanizacion": "estate",
+	"urb":          "estate",
+
+	// English
+	"street":    "street",
+	"st":        "street",
+	"str":       "street",
+	"avenue":    "avenue",
+	"ave":       "avenue",
+	"road":      "road",
+	"rd":        "road",
+	"boulevard": "boulevard",
+	"blvd":      "boulevard",
+	"drive":     "drive",
+	"dr":        "drive",
+	"lane":      "lane",
+	"ln":        "lane",
+	"court":     "court",
+	"ct":        "court",
+	"place":     "place",
+	"highway":   "highway",
+	"hwy":       "highway",
+	"parkway":   "parkway",
+	"pkwy":      "parkway",
+	"square":    "square",
+	"sq":        "square",
+	"terrace":   "terrace",
+	"ter":       "terrace",
+	"way":       "way",
+	"circle":    "circle",
+	"cir":       "circle",
+	"alley":     "alley",
+}
+
+// numberMarkers precede the street number.
+var numberMarkers = map[string]bool{
+	"#":      true,
+	"no":     true,
+	"nº":     true,
+	"n°":     true,
+	"no°":    true,
+	"num":    true,
+	"numero": true,
+	"number": true,
+	"nro":    true,
+}
+
+// unitMarkers precede a floor, apartment or suite, mapped to a canonical name.
+var unitMarkers = map[string]string{
+	"piso":         "floor",
+	"planta":       "floor",
+	"floor":        "floor",
+	"fl":           "floor",
+	"apartamento":  "apartment",
+	"apto":         "apartment",
+	"apt":          "apartment",
+	"apartment":    "apartment",
+	"departamento": "apartment",
+	"depto":        "apartment",
+	"dpto":         "apartment",
+	"puerta":       "door",
+	"pta":          "door",
+	"oficina":      "office",
+	"of":           "office",
+	"office":       "office",
+	"local":        "suite",
+	"suite":        "suite",
+	"ste":          "suite",
+	"unit":         "unit",
+	"bloque":       "block",
+	"bl":           "block",
+	"interior":     "interior",
+	"int":          "interior",
+	"escalera":     "stairs",
+	"esc":          "stairs",
+}
+
+// ambiguousMarkers are the unit markers that are also common words, like the English "of",
+// only taken as markers when a number or a letter follows them.
+var ambiguousMarkers = map[string]bool{
+	"of":    true,
+	"int":   true,
+	"local": true,
+	"bl":    true,
+	"esc":   true,
+}
@@ -0,0 +1,101 @@
+package address
+
+import (
+	ustrings "golibs/cmd/strings"
+)
+
+// Weights sets how much each component adds to the address score.
+type Weights struct {
+	StreetType float64
+	StreetName float64
+	Number     float64
+	Unit       float64
+	PostalCode float64
+	Locality   float64
+}
+
+var DefaultWeights = Weights{
+	StreetType: 0.1,
+	StreetName: 0.35,
+	Number:     0.25,
+	Unit:       0.1,
+	PostalCode: 0.15,
+	Locality:   0.05,
+}
+
+type (
+	Match struct {
+		Score      float64
+		Components Components
+	}
+	// Components holds the similarity of every address component. A component
+	// missing in both addresses is reported as 1 and a component missing in only
+	// one of them as 0, in both cases it is left out of the Score.
+	Components struct {
+		StreetType float64
+		StreetName float64
+		Number     float64
+		Unit       float64
+		PostalCode float64
+		Locality   float64
+	}
+)
+
+// GetSimilarity parses both addresses and compares them with DefaultWeights.
+func GetSimilarity(source, target string) Match {
+	return GetAddressSimilarity(Parse(source), Parse(target), DefaultWeights)
+}
+
+// GetAddressSimilarity compares two parsed addresses component by component and returns
+// the weighted mean of the components present in both.
+func GetAddressSimilarity(source, target Address, weights Weights) Match {
+	var match Match
+	var score, total float64
+
+	compare := func(s, t string, weight float64, similarity ustrings.SimilarityFunc) float64 {
+		if s == "" || t == "" {
+			if s == t {
+				return 1
+			}
+			return 0
+		}
+		sim := similarity(s, t)
+		score += sim * weight
+		total += weight
+		return sim
+	}
+
+	match.Components = Components{
+		StreetType: compare(source.StreetType, target.StreetType, weights.StreetType, compareExact),
+		StreetName: compare(source.StreetName, target.StreetName, weights.StreetName, compareName),
+		Number:     compare(source.Number, target.Number, weights.Number, compareCode),
+		Unit:       compare(source.Unit, target.Unit, weights.Unit, compareCode),
+		PostalCode: compare(source.PostalCode, target.PostalCode, weights.PostalCode, compareCode),
+		Locality:   compare(source.Locality, target.Locality, weights.Locality, compareName),
+	}
+
+	if total > 0 {
+		match.Score = score / total
+	}
+
+	return match
+}
+
+// compareExact compares canonical values, such as the street types.
+func compareExact(source, target string) float64 {
+	if source == target {
+		return 1
+	}
+	return 0
+}
+
+// compareName compares words with the mean of Monge-Elkan and Jaro-Winkler, so reordered or missing
+// words and typos are both tolerated.
+func compareName(source, target string) float64 {
+	return (ustrings.GetMongeElkanSimilarity(source, target, nil) + ustrings.GetJaroWinklerSimilarity(source, target)) / 2
+}
+
+// compareCode compares numbers and codes, where any edit matters, with Levenshtein.
+func compareCode(source, target string) float64 {
+	return ustrings.GetLevenshteinSimilarity(source, target, ustrings.DefaultOptions)
+}
@@ -0,0 +1,712 @@
+package strings
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrInvalidPattern is returned by CompileApprox when the pattern can not be parsed.
+var ErrInvalidPattern = errors.New("invalid approximate pattern")
+
+// ApproxRegexp is a regular expression matched allowing errors, in the spirit of TRE and agrep.
+// Every error has the cost given by Options: a text character missing from the pattern is an
+// insertion, a pattern character missing from the text a deletion, and a different one a
+// substitution.
+//
+// Like the rest of the package the match ignores case and accents. The supported syntax is
+// literals, ".", classes like "[a-z]" and "[^0-9]", the escapes \s \d \w \S \D \W, groups,
+// alternation, the anchors ^ and $, and the quantifiers * + ? {n} {n,} {n,m}. A { that does
+// not start a quantifier is a literal, as in Go's regexp.
+type ApproxRegexp struct {
+	pattern string
+	states  []approxState
+	start   int
+}
+
+// ApproxMatch is a match of an ApproxRegexp. Start and End are byte offsets in the text.
+type ApproxMatch struct {
+	Start int
+	End   int
+	Cost  float64
+}
+
+type approxKind int
+
+const (
+	approxChar approxKind = iota
+	approxEpsilon
+	approxSplit
+	approxBegin
+	approxEnd
+	approxAccept
+)
+
+type approxState struct {
+	kind  approxKind
+	class runeClass
+	out   int
+	out1  int
+}
+
+// CompileApprox parses pattern into an ApproxRegexp.
+func CompileApprox(pattern string) (*ApproxRegexp, error) {
+	p := &approxParser{pattern: []rune(pattern)}
+
+	tree, err := p.parseAlternate()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.pattern) {
+		return nil, p.errorf("unexpected %q", p.pattern[p.pos])
+	}
+
+	re := &ApproxRegexp{pattern: pattern}
+	frag := re.compile(tree)
+	accept := re.add(approxAccept, runeClass{})
+	re.patch(frag.outs, accept)
+	re.start = frag.start
+
+	return re, nil
+}
+
+// MustCompileApprox is like CompileApprox but panics if the pattern can not be parsed.
+func MustCompileApprox(pattern string) *ApproxRegexp {
+	re, err := CompileApprox(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// String return the source pattern.
+func (re *ApproxRegexp) String() string {
+	return re.pattern
+}
+
+// Match report whether text contains a match costing at most maxCost.
+func (re *ApproxRegexp) Match(text string, maxCost float64, options Options) bool {
+	_, ok := re.Find(text, maxCost, options)
+	return ok
+}
+
+// Find return the cheapest match costing at most maxCost, the leftmost and then the
+// longest one when several cost the same.
+func (re *ApproxRegexp) Find(text string, maxCost float64, options Options) (ApproxMatch, bool) {
+	matches := re.FindAll(text, maxCost, options, 1)
+	if len(matches) == 0 {
+		return ApproxMatch{}, false
+	}
+	return matches[0], true
+}
+
+// FindAll return up to n non overlapping matches costing at most maxCost, the cheapest
+// ones first chosen, in order of appearance. A negative n return all of them.
+func (re *ApproxRegexp) FindAll(text string, maxCost float64, options Options, n int) []ApproxMatch {
+	candidates := re.scan(text, maxCost, options)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Cost != b.Cost {
+			return a.Cost < b.Cost
+		}
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		return a.End-a.Start > b.End-b.Start
+	})
+
+	var matches []ApproxMatch
+	for _, c := range candidates {
+		if n >= 0 && len(matches) == n {
+			break
+		}
+		overlaps := false
+		for _, m := range matches {
+			if c.Start < m.End && m.Start < c.End || c.Start == m.Start {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			matches = append(matches, c)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+
+	return matches
+}
+
+// scan runs the automaton over text and returns, for every end position, the cheapest
+// match ending there.
+func (re *ApproxRegexp) scan(text string, maxCost float64, options Options) []ApproxMatch {
+	var runes []rune
+	var offsets []int
+	for i, r := range text {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		runes = append(runes, foldRune(r))
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+
+	current := newApproxColumn(len(re.states))
+	next := newApproxColumn(len(re.states))
+
+	var candidates []ApproxMatch
+	for j := 0; j <= len(runes); j++ {
+		current.set(re.start, 0, j)
+		re.closure(current, j, len(runes), maxCost, options)
+
+		if c := current.cost[len(re.states)-1]; c <= maxCost {
+			start := current.start[len(re.states)-1]
+			candidates = append(candidates, ApproxMatch{Start: offsets[start], End: offsets[j], Cost: c})
+		}
+
+		if j == len(runes) {
+			break
+		}
+
+		next.reset()
+		for s, c := range current.cost {
+			if math.IsInf(c, 1) {
+				continue
+			}
+			start := current.start[s]
+			state := re.states[s]
+
+			if state.kind == approxAccept {
+				continue
+			}
+
+			// insertion, the text character is not in the pattern
+			next.set(s, c+options.InsCost, start)
+
+			if state.kind == approxChar {
+				if state.class.matches(runes[j]) {
+					next.set(state.out, c, start)
+				} else {
+					next.set(state.out, c+options.SubCost, start)
+				}
+			}
+		}
+		current, next = next, current
+	}
+
+	return candidates
+}
+
+// closure follows the epsilon transitions and the deletions of pattern characters
+// until no state gets cheaper.
+func (re *ApproxRegexp) closure(col *approxColumn, j, length int, maxCost float64, options Options) {
+	queue := col.queue[:0]
+	for s, c := range col.cost {
+		if c <= maxCost {
+			queue = append(queue, s)
+		}
+	}
+
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+
+		c, start := col.cost[s], col.start[s]
+		if c > maxCost {
+			continue
+		}
+
+		var targets [2]int
+		var costs [2]float64
+		count := 0
+
+		state := re.states[s]
+		switch state.kind {
+		case approxEpsilon:
+			targets[0], costs[0] = state.out, c
+			count = 1
+		case approxSplit:
+			targets[0], costs[0] = state.out, c
+			targets[1], costs[1] = state.out1, c
+			count = 2
+		case approxBegin:
+			if j == 0 {
+				targets[0], costs[0] = state.out, c
+				count = 1
+			}
+		case approxEnd:
+			if j == length {
+				targets[0], costs[0] = state.out, c
+				count = 1
+			}
+		case approxChar:
+			// deletion, the pattern character is not in the text
+			targets[0], costs[0] = state.out, c+options.DelCost
+			count = 1
+		}
+
+		for i := 0; i < count; i++ {
+			if col.set(targets[i], costs[i], start) {
+				queue = append(queue, targets[i])
+			}
+		}
+	}
+
+	col.queue = queue
+}
+
+type approxColumn struct {
+	cost  []float64
+	start []int
+	queue []int
+}
+
+func newApproxColumn(size int) *approxColumn {
+	col := &approxColumn{
+		cost:  make([]float64, size),
+		start: make([]int, size),
+	}
+	col.reset()
+	return col
+}
+
+func (col *approxColumn) reset() {
+	for i := range col.cost {
+		col.cost[i] = math.Inf(1)
+		col.start[i] = 0
+	}
+}
+
+// set lowers the cost of state s, preferring the leftmost start on ties, and report if it changed.
+func (col *approxColumn) set(s int, cost float64, start int) bool {
+	if cost < col.cost[s] || (cost == col.cost[s] && start < col.start[s]) {
+		col.cost[s] = cost
+		col.start[s] = start
+		return true
+	}
+	return false
+}
+
+// Thompson construction
+
+// approxOut points to an unpatched exit of a state. The states slice grows while
+// compiling, so exits are kept as indexes instead of pointers.
+type approxOut struct {
+	state  int
+	second bool
+}
+
+type approxFrag struct {
+	start int
+	outs  []approxOut
+}
+
+func (re *ApproxRegexp) add(kind approxKind, class runeClass) int {
+	re.states = append(re.states, approxState{kind: kind, class: class, out: -1, out1: -1})
+	return len(re.states) - 1
+}
+
+func (re *ApproxRegexp) patch(outs []approxOut, target int) {
+	for _, out := range outs {
+		if out.second {
+			re.states[out.state].out1 = target
+		} else {
+			re.states[out.state].out = target
+		}
+	}
+}
+
+func (re *ApproxRegexp) compile(n *approxNode) approxFrag {
+	switch n.kind {
+	case nodeEmpty, nodeClass, nodeBegin, nodeEnd:
+		s := re.add(approxKinds[n.kind], n.class)
+		return approxFrag{start: s, outs: []approxOut{{state: s}}}
+	case nodeConcat:
+		frag := re.compile(n.subs[0])
+		for _, sub := range n.subs[1:] {
+			next := re.compile(sub)
+			re.patch(frag.outs, next.start)
+			frag.outs = next.outs
+		}
+		return frag
+	case nodeAlternate:
+		frag := re.compile(n.subs[0])
+		for _, sub := range n.subs[1:] {
+			other := re.compile(sub)
+			s := re.add(approxSplit, runeClass{})
+			re.states[s].out = frag.start
+			re.states[s].out1 = other.start
+			frag = approxFrag{start: s, outs: append(frag.outs, other.outs...)}
+		}
+		return frag
+	case nodeRepeat:
+		return re.compileRepeat(n)
+	}
+	panic("unknown approximate node")
+}
+
+var approxKinds = map[approxNodeKind]approxKind{
+	nodeEmpty: approxEpsilon,
+	nodeClass: approxChar,
+	nodeBegin: approxBegin,
+	nodeEnd:   approxEnd,
+}
+
+// compileRepeat chains min copies of the sub expression followed by a loop, when there
+// is no maximum, or by max-min optional copies.
+func (re *ApproxRegexp) compileRepeat(n *approxNode) approxFrag {
+	sub := n.subs[0]
+
+	frag := re.compile(&approxNode{kind: nodeEmpty})
+	chain := func(next approxFrag) {
+		re.patch(frag.outs, next.start)
+		frag.outs = next.outs
+	}
+
+	for i := 0; i < n.min; i++ {
+		chain(re.compile(sub))
+	}
+
+	if n.max < 0 {
+		body := re.compile(sub)
+		s := re.add(approxSplit, runeClass{})
+		re.states[s].out = body.start
+		re.patch(body.outs, s)
+		chain(approxFrag{start: s, outs: []approxOut{{state: s, second: true}}})
+		return frag
+	}
+
+	var exits []approxOut
+	for i := n.min; i < n.max; i++ {
+		body := re.compile(sub)
+		s := re.add(approxSplit, runeClass{})
+		re.states[s].out = body.start
+		exits = append(exits, approxOut{state: s, second: true})
+		chain(approxFrag{start: s, outs: body.outs})
+	}
+	frag.outs = append(frag.outs, exits...)
+
+	return frag
+}
+
+// Parser
+
+type approxNodeKind int
+
+const (
+	nodeEmpty approxNodeKind = iota
+	nodeClass
+	nodeBegin
+	nodeEnd
+	nodeConcat
+	nodeAlternate
+	nodeRepeat
+)
+
+type approxNode struct {
+	kind     approxNodeKind
+	class    runeClass
+	subs     []*approxNode
+	min, max int
+}
+
+type approxParser struct {
+	pattern []rune
+	pos     int
+}
+
+func (p *approxParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w at position %d: %s", ErrInvalidPattern, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *approxParser) more() bool {
+	return p.pos < len(p.pattern)
+}
+
+func (p *approxParser) peek() rune {
+	return p.pattern[p.pos]
+}
+
+func (p *approxParser) parseAlternate() (*approxNode, error) {
+	var subs []*approxNode
+	for {
+		sub, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+		if !p.more() || p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+	if len(subs) == 1 {
+		return subs[0], nil
+	}
+	return &approxNode{kind: nodeAlternate, subs: subs}, nil
+}
+
+func (p *approxParser) parseConcat() (*approxNode, error) {
+	var subs []*approxNode
+	for p.more() && p.peek() != '|' && p.peek() != ')' {
+		sub, err := p.parseRepeat()
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	switch len(subs) {
+	case 0:
+		return &approxNode{kind: nodeEmpty}, nil
+	case 1:
+		return subs[0], nil
+	}
+	return &approxNode{kind: nodeConcat, subs: subs}, nil
+}
+
+func (p *approxParser) parseRepeat() (*approxNode, error) {
+	atom, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.more() {
+		min, max := -1, -1
+		switch p.peek() {
+		case '*':
+			min, max = 0, -1
+			p.pos++
+		case '+':
+			min, max = 1, -1
+			p.pos++
+		case '?':
+			min, max = 0, 1
+			p.pos++
+		case '{':
+			var ok bool
+			min, max, ok, err = p.parseBraces()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				// a literal {, read by the next atom
+				return atom, nil
+			}
+		default:
+			return atom, nil
+		}
+		if atom.kind == nodeBegin || atom.kind == nodeEnd {
+			return nil, p.errorf("missing argument to repetition operator")
+		}
+		atom = &approxNode{kind: nodeRepeat, subs: []*approxNode{atom}, min: min, max: max}
+	}
+
+	return atom, nil
+}
+
+// parseBraces parses {n}, {n,} or {n,m}. When the braces are not one of them, like an
+// unterminated {, it return false without moving, and the { is a literal as in Go's regexp.
+func (p *approxParser) parseBraces() (int, int, bool, error) {
+	start := p.pos
+	p.pos++ // {
+	min, ok := p.parseInt()
+	max := min
+	if ok && p.more() && p.peek() == ',' {
+		p.pos++
+		max = -1
+		if n, ok := p.parseInt(); ok {
+			max = n
+		}
+	}
+	if !ok || !p.more() || p.peek() != '}' {
+		p.pos = start
+		return 0, 0, false, nil
+	}
+	p.pos++
+	if max >= 0 && max < min {
+		return 0, 0, false, p.errorf("invalid repetition range {%d,%d}", min, max)
+	}
+	if min > 1000 || max > 1000 {
+		return 0, 0, false, p.errorf("repetition count too large")
+	}
+	return min, max, true, nil
+}
+
+func (p *approxParser) parseInt() (int, bool) {
+	start := p.pos
+	n := 0
+	for p.more() && '0' <= p.peek() && p.peek() <= '9' {
+		// larger counts are rejected anyway
+		n = min(n*10+int(p.peek()-'0'), 100000)
+		p.pos++
+	}
+	return n, p.pos > start
+}
+
+func (p *approxParser) parseAtom() (*approxNode, error) {
+	r := p.peek()
+	p.pos++
+
+	switch r {
+	case '(':
+		sub, err := p.parseAlternate()
+		if err != nil {
+			return nil, err
+		}
+		if !p.more() || p.peek() != ')' {
+			return nil, p.errorf("missing closing )")
+		}
+		p.pos++
+		return sub, nil
+	case '[':
+		class, err := p.parseClass()
+		if err != nil {
+			return nil, err
+		}
+		return &approxNode{kind: nodeClass, class: class}, nil
+	case '.':
+		return &approxNode{kind: nodeClass, class: runeClass{negate: true}}, nil
+	case '^':
+		return &approxNode{kind: nodeBegin}, nil
+	case '$':
+		return &approxNode{kind: nodeEnd}, nil
+	case '\\':
+		class, err := p.parseEscape()
+		if err != nil {
+			return nil, err
+		}
+		return &approxNode{kind: nodeClass, class: class}, nil
+	case '*', '+', '?':
+		p.pos--
+		return nil, p.errorf("missing argument to repetition operator")
+	case '{':
+		p.pos--
+		start := p.pos
+		if _, _, ok, _ := p.parseBraces(); ok {
+			p.pos = start
+			return nil, p.errorf("missing argument to repetition operator")
+		}
+		p.pos++
+	}
+
+	return &approxNode{kind: nodeClass, class: literalClass(r)}, nil
+}
+
+func (p *approxParser) parseEscape() (runeClass, error) {
+	if !p.more() {
+		return runeClass{}, p.errorf("trailing backslash")
+	}
+	r := p.peek()
+	p.pos++
+
+	switch r {
+	case 's', 'd', 'w':
+		return runeClass{tables: []func(rune) bool{perlClasses[r]}}, nil
+	case 'S', 'D', 'W':
+		return runeClass{tables: []func(rune) bool{perlClasses[unicode.ToLower(r)]}, negate: true}, nil
+	case 't':
+		return literalClass('\t'), nil
+	case 'n':
+		return literalClass('\n'), nil
+	}
+	return literalClass(r), nil
+}
+
+func (p *approxParser) parseClass() (runeClass, error) {
+	var class runeClass
+	if p.more() && p.peek() == '^' {
+		class.negate = true
+		p.pos++
+	}
+
+	first := true
+	for p.more() && (p.peek() != ']' || first) {
+		first = false
+		r := p.peek()
+		p.pos++
+
+		if r == '\\' {
+			escaped, err := p.parseEscape()
+			if err != nil {
+				return class, err
+			}
+			class.ranges = append(class.ranges, escaped.ranges...)
+			if escaped.negate {
+				for _, table := range escaped.tables {
+					table := table
+					class.tables = append(class.tables, func(r rune) bool { return !table(r) })
+				}
+			} else {
+				class.tables = append(class.tables, escaped.tables...)
+			}
+			continue
+		}
+
+		lo, hi := r, r
+		if p.pos+1 < len(p.pattern) && p.peek() == '-' && p.pattern[p.pos+1] != ']' {
+			hi = p.pattern[p.pos+1]
+			p.pos += 2
+			if hi < lo {
+				return class, p.errorf("invalid class range %c-%c", lo, hi)
+			}
+		}
+		class.ranges = append(class.ranges, foldRune(lo), foldRune(hi))
+	}
+
+	if !p.more() {
+		return class, p.errorf("missing closing ]")
+	}
+	p.pos++
+
+	return class, nil
+}
+
+// runeClass matches a folded rune against ranges, stored as lo, hi pairs, and tables.
+type runeClass struct {
+	ranges []rune
+	tables []func(rune) bool
+	negate bool
+}
+
+func literalClass(r rune) runeClass {
+	r = foldRune(r)
+	return runeClass{ranges: []rune{r, r}}
+}
+
+func (c runeClass) matches(r rune) bool {
+	found := false
+	for i := 0; i+1 < len(c.ranges) && !found; i += 2 {
+		found = c.ranges[i] <= r && r <= c.ranges[i+1]
+	}
+	for i := 0; i < len(c.tables) && !found; i++ {
+		found = c.tables[i](r)
+	}
+	return found != c.negate
+}
+
+var perlClasses = map[rune]func(rune) bool{
+	's': unicode.IsSpace,
+	'd': unicode.IsDigit,
+	'w': func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	},
+}
+
+// foldRune lowercase r and removes its accent, like strNormalization does for a whole string.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		return unicode.ToLower(r)
+	}
+	var buf [utf8.UTFMax]byte
+	decomposed := norm.NFD.Append(nil, buf[:utf8.EncodeRune(buf[:], r)]...)
+	base, _ := utf8.DecodeRune(decomposed)
+	return unicode.ToLower(base)
+}
@@ -0,0 +1,174 @@
+package strings_test
+
+import (
+	"errors"
+	"golibs/cmd/strings"
+	"reflect"
+	"testing"
+)
+
+func TestApproxRegexp_FindAll(t *testing.T) {
+	type args struct {
+		pattern string
+		text    string
+		maxCost float64
+		options strings.Options
+	}
+	tests := []struct {
+		name string
+		args args
+		want []strings.ApproxMatch
+	}{
+		{
+			name: "Exact with accents",
+			args: args{
+				pattern: `reyn[iy]er\s+gonz.l[ez]{2}`,
+				text:    "Reynier González",
+				maxCost: 2,
+				options: strings.DefaultOptions,
+			},
+			want: []strings.ApproxMatch{{Start: 0, End: 17, Cost: 0}},
+		},
+		{
+			name: "Two substitutions",
+			args: args{
+				pattern: `reyn[iy]er\s+gonz.l[ez]{2}`,
+				text:    "Reinier Gonzales",
+				maxCost: 2,
+				options: strings.DefaultOptions,
+			},
+			want: []strings.ApproxMatch{{Start: 0, End: 16, Cost: 2}},
+		},
+		{
+			name: "Substitution cost",
+			args: args{
+				pattern: `reyn[iy]er\s+gonz.l[ez]{2}`,
+				text:    "Reinier Gonzales",
+				maxCost: 2,
+				options: strings.Options{
+					InsCost: 1,
+					DelCost: 1,
+					SubCost: 1.5,
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "Several matches",
+			args: args{
+				pattern: `reyn[iy]er\s+gonz.l[ez]{2}`,
+				text:    "reynier gonzalez cruz y Reyner Gonsalez",
+				maxCost: 2,
+				options: strings.DefaultOptions,
+			},
+			want: []strings.ApproxMatch{{Start: 0, End: 16, Cost: 0}, {Start: 24, End: 39, Cost: 2}},
+		},
+		{
+			name: "Optional",
+			args: args{
+				pattern: "colou?r",
+				text:    "the color and colour and colr",
+				maxCost: 0,
+				options: strings.DefaultOptions,
+			},
+			want: []strings.ApproxMatch{{Start: 4, End: 9, Cost: 0}, {Start: 14, End: 20, Cost: 0}},
+		},
+		{
+			name: "Alternation with deletion",
+			args: args{
+				pattern: "(cat|dog)s?",
+				text:    "cats dgo",
+				maxCost: 1,
+				options: strings.DefaultOptions,
+			},
+			want: []strings.ApproxMatch{{Start: 0, End: 4, Cost: 0}, {Start: 5, End: 7, Cost: 1}},
+		},
+		{
+			name: "Anchors with insertion",
+			args: args{
+				pattern: "^abc$",
+				text:    "abxc",
+				maxCost: 1,
+				options: strings.DefaultOptions,
+			},
+			want: []strings.ApproxMatch{{Start: 0, End: 4, Cost: 1}},
+		},
+		{
+			name: "Bounded repetition",
+			args: args{
+				pattern: "a{2,3}b",
+				text:    "aab aaab ab",
+				maxCost: 0,
+				options: strings.DefaultOptions,
+			},
+			want: []strings.ApproxMatch{{Start: 0, End: 3, Cost: 0}, {Start: 4, End: 8, Cost: 0}},
+		},
+		{
+			name: "Literal brace",
+			args: args{
+				pattern: "a{b,}",
+				text:    "xa{b,}x",
+				maxCost: 0,
+				options: strings.DefaultOptions,
+			},
+			want: []strings.ApproxMatch{{Start: 1, End: 6, Cost: 0}},
+		},
+		{
+			name: "No match",
+			args: args{
+				pattern: `reyn[iy]er\s+gonz.l[ez]{2}`,
+				text:    "Arelys Rivero",
+				maxCost: 2,
+				options: strings.DefaultOptions,
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := strings.MustCompileApprox(tt.args.pattern)
+			if got := re.FindAll(tt.args.text, tt.args.maxCost, tt.args.options, -1); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApproxRegexp_Find(t *testing.T) {
+	re := strings.MustCompileApprox("gonzalez")
+
+	got, ok := re.Find("Gonzales y González", 1, strings.DefaultOptions)
+	want := strings.ApproxMatch{Start: 11, End: 20, Cost: 0}
+	if !ok || got != want {
+		t.Errorf("Find() = %v, %v, want %v", got, ok, want)
+	}
+
+	if re.Match("Gonzalo", 1, strings.DefaultOptions) {
+		t.Errorf("Match() = true, want false")
+	}
+}
+
+func TestCompileApprox(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		wantErr error
+	}{
+		{name: "Valid", pattern: `(a|b)*c{1,2}[^x-z]\d+$`, wantErr: nil},
+		{name: "Missing parenthesis", pattern: "a(b", wantErr: strings.ErrInvalidPattern},
+		{name: "Missing bracket", pattern: "[a-", wantErr: strings.ErrInvalidPattern},
+		{name: "Missing argument", pattern: "*a", wantErr: strings.ErrInvalidPattern},
+		{name: "Invalid range", pattern: "a{3,1}", wantErr: strings.ErrInvalidPattern},
+		{name: "Unterminated brace", pattern: "a{", wantErr: nil},
+		{name: "Unterminated count", pattern: "a{2,", wantErr: nil},
+		{name: "Brace without argument", pattern: "{2}a", wantErr: strings.ErrInvalidPattern},
+		{name: "Trailing backslash", pattern: `ab\`, wantErr: strings.ErrInvalidPattern},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := strings.CompileApprox(tt.pattern); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CompileApprox() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,385 @@
+package strings
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LevenshteinAutomaton is a deterministic automaton accepting the words within Levenshtein
+// distance k of a query, built with the construction of Schulz and Mihov. A state is a set of
+// positions i#e, i characters of the query read with e errors, kept relative to its smallest
+// i, the offset of the state in the query. The transitions of these relative sets only depend
+// on k and on the characteristic vector of the input character in the 2k+1 query characters
+// from the offset, so they form a parametric automaton shared by the queries with the same k.
+// The automaton of a query pairs the parametric states with their offsets, and every state has
+// one transition per distinct query character plus a default one for any other character.
+//
+// The parametric transitions are computed the first time a query needs them, and kept for
+// the later queries when k is at most 4.
+//
+// The automaton compares runes as they are, normalize the query and the words beforehand to
+// get the behavior of the rest of the package. It is safe for concurrent use once built.
+type LevenshteinAutomaton struct {
+	query    []rune
+	k        int
+	alphabet map[rune]int
+	// transitions holds, for every state, the next state for each alphabet index, the
+	// default transition being the last one. A negative state is the dead state.
+	transitions [][]int
+	distances   []int
+}
+
+// AutomatonResult is a word accepted by a LevenshteinAutomaton.
+type AutomatonResult struct {
+	Word     string
+	Distance int
+}
+
+// NewLevenshteinAutomaton builds the automaton of the words within distance k of query.
+func NewLevenshteinAutomaton(query string, k int) *LevenshteinAutomaton {
+	if k < 0 {
+		k = 0
+	}
+
+	a := &LevenshteinAutomaton{
+		query:    []rune(query),
+		k:        k,
+		alphabet: make(map[rune]int),
+	}
+
+	var symbols []rune
+	for _, r := range a.query {
+		if _, ok := a.alphabet[r]; !ok {
+			a.alphabet[r] = len(symbols)
+			symbols = append(symbols, r)
+		}
+	}
+
+	p := newParametricAutomaton(k)
+	// a larger window would only read past the end of the query
+	width := 2*min(k, len(a.query)) + 1
+
+	type queryState struct {
+		state, offset int
+	}
+	states := map[queryState]int{{}: 0}
+	pending := []queryState{{}}
+	vector := make([]bool, 0, width)
+
+	for s := 0; s < len(pending); s++ {
+		current := pending[s]
+		a.distances = append(a.distances, a.distance(p.positions(current.state), current.offset))
+
+		window := a.query[current.offset:min(len(a.query), current.offset+width)]
+		transitions := make([]int, len(symbols)+1)
+		for i := range transitions {
+			vector = vector[:0]
+			for _, r := range window {
+				vector = append(vector, i < len(symbols) && r == symbols[i])
+			}
+
+			t := p.step(current.state, vector)
+			if t.state < 0 {
+				transitions[i] = -1
+				continue
+			}
+			next := queryState{state: t.state, offset: current.offset + t.shift}
+			id, ok := states[next]
+			if !ok {
+				id = len(pending)
+				states[next] = id
+				pending = append(pending, next)
+			}
+			transitions[i] = id
+		}
+		a.transitions = append(a.transitions, transitions)
+	}
+
+	return a
+}
+
+// distance return the distance of the words ending with positions at offset: the errors plus
+// the query characters left, the fewest of any position.
+func (a *LevenshteinAutomaton) distance(positions []levPosition, offset int) int {
+	d := math.MaxInt
+	for _, pos := range positions {
+		d = min(d, pos.e+len(a.query)-offset-pos.i)
+	}
+	return d
+}
+
+// maxSharedDistance is the largest k whose parametric automaton is kept for later queries.
+// Larger ones grow quickly and are rarely reused.
+const maxSharedDistance = 4
+
+var parametricAutomata sync.Map // int -> *parametricAutomaton
+
+// levPosition is the position i#e of the construction, with i relative to the offset.
+type levPosition struct {
+	i, e int
+}
+
+// parametricAutomaton holds the parametric states and transitions of a distance k, computed
+// as they are needed. The state 0 is the initial one, {0#0}.
+type parametricAutomaton struct {
+	k int
+
+	mu          sync.Mutex
+	states      [][]levPosition
+	index       map[string]int
+	transitions map[parametricInput]parametricTransition
+}
+
+// parametricInput is a state and the characteristic vector read, with its length and its
+// bits packed in a string.
+type parametricInput struct {
+	state  int
+	length int
+	bits   string
+}
+
+// parametricTransition is the state reached, negative for the dead state, and how far the
+// offset moves.
+type parametricTransition struct {
+	state int
+	shift int
+}
+
+// newParametricAutomaton return the parametric automaton of k, the shared one when k is at
+// most maxSharedDistance.
+func newParametricAutomaton(k int) *parametricAutomaton {
+	if k <= maxSharedDistance {
+		if p, ok := parametricAutomata.Load(k); ok {
+			return p.(*parametricAutomaton)
+		}
+	}
+
+	p := &parametricAutomaton{
+		k:           k,
+		index:       make(map[string]int),
+		transitions: make(map[parametricInput]parametricTransition),
+	}
+	p.intern([]levPosition{{}})
+
+	if k <= maxSharedDistance {
+		shared, _ := parametricAutomata.LoadOrStore(k, p)
+		return shared.(*parametricAutomaton)
+	}
+	return p
+}
+
+// positions return the positions of state.
+func (p *parametricAutomaton) positions(state int) []levPosition {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.states[state]
+}
+
+// step return the transition from state reading a character with the characteristic vector.
+func (p *parametricAutomaton) step(state int, vector []bool) parametricTransition {
+	bits := make([]byte, (len(vector)+7)/8)
+	for i, match := range vector {
+		if match {
+			bits[i/8] |= 1 << (i % 8)
+		}
+	}
+	input := parametricInput{state: state, length: len(vector), bits: string(bits)}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t, ok := p.transitions[input]; ok {
+		return t
+	}
+	t := p.transition(p.states[state], vector)
+	p.transitions[input] = t
+	return t
+}
+
+// transition computes the elementary transitions of the positions, drops the subsumed ones
+// and moves the result to its own offset. It must hold mu.
+func (p *parametricAutomaton) transition(positions []levPosition, vector []bool) parametricTransition {
+	var next []levPosition
+	for _, pos := range positions {
+		if pos.i < len(vector) && vector[pos.i] {
+			next = append(next, levPosition{i: pos.i + 1, e: pos.e})
+			continue
+		}
+		if pos.e == p.k {
+			continue
+		}
+		// an inserted character, and a substituted one before the end of the query
+		next = append(next, levPosition{i: pos.i, e: pos.e + 1})
+		if pos.i < len(vector) {
+			next = append(next, levPosition{i: pos.i + 1, e: pos.e + 1})
+		}
+		// deleted query characters up to the first one matching, the later ones are subsumed
+		for j := 1; j <= p.k-pos.e && pos.i+j < len(vector); j++ {
+			if vector[pos.i+j] {
+				next = append(next, levPosition{i: pos.i + j + 1, e: pos.e + j})
+				break
+			}
+		}
+	}
+	if len(next) == 0 {
+		return parametricTransition{state: -1}
+	}
+
+	sort.Slice(next, func(i, j int) bool {
+		if next[i].i != next[j].i {
+			return next[i].i < next[j].i
+		}
+		return next[i].e < next[j].e
+	})
+	kept := next[:0]
+	for i, pos := range next {
+		if i > 0 && pos == next[i-1] {
+			continue
+		}
+		subsumed := false
+		for _, other := range next {
+			if other.e < pos.e && max(other.i-pos.i, pos.i-other.i) <= pos.e-other.e {
+				subsumed = true
+				break
+			}
+		}
+		if !subsumed {
+			kept = append(kept, pos)
+		}
+	}
+
+	shift := kept[0].i
+	for i := range kept {
+		kept[i].i -= shift
+	}
+	return parametricTransition{state: p.intern(kept), shift: shift}
+}
+
+// intern return the state of the sorted positions, adding it the first time. It must hold mu.
+func (p *parametricAutomaton) intern(positions []levPosition) int {
+	key := make([]byte, 0, 2*len(positions))
+	for _, pos := range positions {
+		key = binary.AppendUvarint(key, uint64(pos.i))
+		key = binary.AppendUvarint(key, uint64(pos.e))
+	}
+	if state, ok := p.index[string(key)]; ok {
+		return state
+	}
+
+	state := len(p.states)
+	p.index[string(key)] = state
+	p.states = append(p.states, append([]levPosition(nil), positions...))
+	return state
+}
+
+// States return the number of states of the automaton.
+func (a *LevenshteinAutomaton) States() int {
+	return len(a.transitions)
+}
+
+// Start return the initial state.
+func (a *LevenshteinAutomaton) Start() int {
+	return 0
+}
+
+// Step return the state reached from state reading r, negative when it is the dead state.
+func (a *LevenshteinAutomaton) Step(state int, r rune) int {
+	if state < 0 {
+		return -1
+	}
+	i, ok := a.alphabet[r]
+	if !ok {
+		i = len(a.alphabet)
+	}
+	return a.transitions[state][i]
+}
+
+// Distance return the distance of the words ending in state, and whether it is accepting.
+func (a *LevenshteinAutomaton) Distance(state int) (int, bool) {
+	if state < 0 {
+		return 0, false
+	}
+	d := a.distances[state]
+	return d, d <= a.k
+}
+
+// Match report the distance between word and the query, and whether it is at most k.
+func (a *LevenshteinAutomaton) Match(word string) (int, bool) {
+	state := a.Start()
+	for _, r := range word {
+		if state = a.Step(state, r); state < 0 {
+			return 0, false
+		}
+	}
+	return a.Distance(state)
+}
+
+// FilterSorted return the words of a lexicographically sorted dictionary accepted by the
+// automaton. The states of the prefix shared with the previous word are reused and, when a
+// prefix reaches the dead state, all the words starting with it are skipped at once.
+func (a *LevenshteinAutomaton) FilterSorted(dictionary []string) []AutomatonResult {
+	var results []AutomatonResult
+
+	// states[i] is the state after reading the first i bytes of previous
+	states := []int{a.Start()}
+	var previous string
+
+	for i := 0; i < len(dictionary); {
+		word := dictionary[i]
+
+		common := commonPrefix(previous, word)
+		if common > len(states)-1 {
+			common = len(states) - 1
+		}
+		states = states[:common+1]
+		previous = word
+
+		state := states[common]
+		dead := false
+		for pos, r := range word[common:] {
+			if state = a.Step(state, r); state < 0 {
+				// skip every word starting with the dead prefix
+				prefix := word[:common+pos+len(string(r))]
+				i += sort.Search(len(dictionary)-i, func(j int) bool {
+					return !strings.HasPrefix(dictionary[i+j], prefix)
+				})
+				states = states[:1]
+				previous = ""
+				dead = true
+				break
+			}
+			for len(states) < common+pos+len(string(r)) {
+				states = append(states, -1)
+			}
+			states = append(states, state)
+		}
+		if dead {
+			continue
+		}
+
+		if d, ok := a.Distance(state); ok {
+			results = append(results, AutomatonResult{Word: word, Distance: d})
+		}
+		i++
+	}
+
+	return results
+}
+
+// commonPrefix return the length in bytes of the common prefix of a and b, on a rune boundary.
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	for n > 0 && n < len(b) && !isRuneStart(b[n]) {
+		n--
+	}
+	return n
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
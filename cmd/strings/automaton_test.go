@@ -0,0 +1,187 @@
+package strings_test
+
+import (
+	"golibs/cmd/strings"
+	"math/rand"
+	"reflect"
+	"sort"
+	stdstrings "strings"
+	"testing"
+)
+
+func TestLevenshteinAutomaton_Match(t *testing.T) {
+	automaton := strings.NewLevenshteinAutomaton("gonzalez", 2)
+
+	tests := []struct {
+		word     string
+		distance int
+		ok       bool
+	}{
+		{word: "gonzalez", distance: 0, ok: true},
+		{word: "gonzales", distance: 1, ok: true},
+		{word: "gozalez", distance: 1, ok: true},
+		{word: "gonsales", distance: 2, ok: true},
+		{word: "gomez", ok: false},
+		{word: "", ok: false},
+		{word: "gonzalezzzz", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			distance, ok := automaton.Match(tt.word)
+			if ok != tt.ok || ok && distance != tt.distance {
+				t.Errorf("Match(%q) = %d, %v, want %d, %v", tt.word, distance, ok, tt.distance, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLevenshteinAutomaton_Brute(t *testing.T) {
+	alphabet := []rune("abcñ")
+	random := rand.New(rand.NewSource(1))
+	randomWord := func(max int) string {
+		runes := make([]rune, random.Intn(max+1))
+		for i := range runes {
+			runes[i] = alphabet[random.Intn(len(alphabet))]
+		}
+		return string(runes)
+	}
+
+	for i := 0; i < 200; i++ {
+		query, k := randomWord(8), random.Intn(4)
+		automaton := strings.NewLevenshteinAutomaton(query, k)
+		for j := 0; j < 50; j++ {
+			word := randomWord(10)
+			want := editDistance([]rune(query), []rune(word))
+			distance, ok := automaton.Match(word)
+			if ok != (want <= k) || ok && distance != want {
+				t.Fatalf("NewLevenshteinAutomaton(%q, %d).Match(%q) = %d, %v, want %d", query, k, word, distance, ok, want)
+			}
+		}
+	}
+}
+
+func TestLevenshteinAutomaton_FilterSorted(t *testing.T) {
+	dictionary := []string{"casa", "casas", "caso", "cosa", "cueva", "masa", "mesa", "pasa", "perro", "taza"}
+
+	got := strings.NewLevenshteinAutomaton("casa", 1).FilterSorted(dictionary)
+	want := []strings.AutomatonResult{
+		{Word: "casa", Distance: 0},
+		{Word: "casas", Distance: 1},
+		{Word: "caso", Distance: 1},
+		{Word: "cosa", Distance: 1},
+		{Word: "masa", Distance: 1},
+		{Word: "pasa", Distance: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterSorted() = %v, want %v", got, want)
+	}
+}
+
+func TestLevenshteinAutomaton_FilterSortedBrute(t *testing.T) {
+	alphabet := []rune("abñ")
+	random := rand.New(rand.NewSource(2))
+	dictionary := make([]string, 500)
+	for i := range dictionary {
+		runes := make([]rune, random.Intn(7))
+		for j := range runes {
+			runes[j] = alphabet[random.Intn(len(alphabet))]
+		}
+		dictionary[i] = string(runes)
+	}
+	sort.Strings(dictionary)
+
+	for _, query := range []string{"", "a", "abñ", "ññab", "babab"} {
+		for k := 0; k <= 2; k++ {
+			var want []strings.AutomatonResult
+			for _, word := range dictionary {
+				if d := editDistance([]rune(query), []rune(word)); d <= k {
+					want = append(want, strings.AutomatonResult{Word: word, Distance: d})
+				}
+			}
+			got := strings.NewLevenshteinAutomaton(query, k).FilterSorted(dictionary)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("FilterSorted(%q, %d) = %d words, want %d", query, k, len(got), len(want))
+			}
+		}
+	}
+}
+
+func editDistance(a, b []rune) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			diagonal, row[j] = row[j], min(row[j]+1, row[j-1]+1, diagonal+cost)
+		}
+	}
+	return row[len(b)]
+}
+
+func benchmarkDictionary() []string {
+	random := rand.New(rand.NewSource(1))
+	dictionary := make([]string, 20000)
+	for i := range dictionary {
+		runes := make([]rune, 4+random.Intn(8))
+		for j := range runes {
+			runes[j] = rune('a' + random.Intn(26))
+		}
+		dictionary[i] = string(runes)
+	}
+	sort.Strings(dictionary)
+	return dictionary
+}
+
+func BenchmarkLevenshteinAutomaton_FilterSorted(b *testing.B) {
+	dictionary := benchmarkDictionary()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strings.NewLevenshteinAutomaton("gonzalez", 2).FilterSorted(dictionary)
+	}
+}
+
+func BenchmarkGetLevenshteinSimilarity_Dictionary(b *testing.B) {
+	dictionary := benchmarkDictionary()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, word := range dictionary {
+			strings.GetLevenshteinSimilarity("gonzalez", word, strings.DefaultOptions)
+		}
+	}
+}
+
+func TestLevenshteinAutomaton_States(t *testing.T) {
+	// the states pair one of the parametric states of Schulz and Mihov with an offset, so
+	// there are at most (n+1) times as many, 1, 5, 30 and 196 for k from 0 to 3
+	parametric := []int{1, 5, 30, 196}
+	for k, states := range parametric {
+		for _, query := range []string{stdstrings.Repeat("abcdefghij", 10), stdstrings.Repeat("ab", 50), stdstrings.Repeat("a", 100)} {
+			if got, max := strings.NewLevenshteinAutomaton(query, k).States(), (len(query)+1)*states; got > max {
+				t.Errorf("NewLevenshteinAutomaton(%q, %d).States() = %d, want at most %d", query, k, got, max)
+			}
+		}
+	}
+}
+
+func TestLevenshteinAutomaton_LargeK(t *testing.T) {
+	// distances of 256 and more must not share the states of the smaller ones
+	query, k := "aaaaaaaaaa", 300
+	automaton := strings.NewLevenshteinAutomaton(query, k)
+	for n := 250; n <= 310; n += 3 {
+		for _, word := range []string{stdstrings.Repeat("b", n), stdstrings.Repeat("b", n) + "aaaa", stdstrings.Repeat("ab", n/2)} {
+			want := editDistance([]rune(query), []rune(word))
+			distance, ok := automaton.Match(word)
+			if ok != (want <= k) || ok && distance != want {
+				t.Fatalf("Match(%d runes) = %d, %v, want %d", len([]rune(word)), distance, ok, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package strings
+
+import "unicode/utf8"
+
+const wordSize = 64
+
+// bitParallel computes the unit cost Levenshtein distance with the bit-vector algorithm of
+// Myers (1999) as formulated by Hyyrö, splitting patterns longer than 64 runes in blocks.
+// It keeps its buffers so it can be reused between calls.
+type bitParallel struct {
+	// peq holds, for every block, the match mask of each ASCII character.
+	peq []uint64
+	// runes and masks hold the match masks of the non ASCII characters of the pattern.
+	runes []rune
+	masks []uint64
+
+	pv, mv []uint64
+}
+
+func (o Options) unitCosts() bool {
+	return o.InsCost == 1 && o.DelCost == 1 && o.SubCost == 1
+}
+
+// distance return the Levenshtein distance between source and target.
+func (b *bitParallel) distance(source, target []rune) int {
+	pattern, text := source, target
+	if len(pattern) > len(text) {
+		pattern, text = text, pattern
+	}
+
+	if len(pattern) == 0 {
+		return len(text)
+	}
+
+	blocks := (len(pattern) + wordSize - 1) / wordSize
+	b.prepare(pattern, blocks)
+
+	last := uint64(1) << uint((len(pattern)-1)%wordSize)
+	score := len(pattern)
+
+	for _, c := range text {
+		k := -1
+		if c >= utf8.RuneSelf {
+			k = b.index(c)
+		}
+
+		hin := 1
+		for block := 0; block < blocks; block++ {
+			high := uint64(1) << (wordSize - 1)
+			if block == blocks-1 {
+				high = last
+			}
+			hin = b.advance(block, b.mask(c, k, block, blocks), hin, high)
+		}
+		score += hin
+	}
+
+	return score
+}
+
+// prepare builds the match masks of pattern and resets the vertical deltas.
+func (b *bitParallel) prepare(pattern []rune, blocks int) {
+	b.peq = growUints(b.peq, blocks*utf8.RuneSelf)
+	b.pv = growUints(b.pv, blocks)
+	b.mv = growUints(b.mv, blocks)
+	b.runes = b.runes[:0]
+	b.masks = b.masks[:0]
+
+	for i := range b.pv {
+		b.pv[i] = ^uint64(0)
+	}
+
+	for i, c := range pattern {
+		block, bit := i/wordSize, uint64(1)<<uint(i%wordSize)
+
+		if c < utf8.RuneSelf {
+			b.peq[block*utf8.RuneSelf+int(c)] |= bit
+			continue
+		}
+
+		k := b.index(c)
+		if k < 0 {
+			k = len(b.runes)
+			b.runes = append(b.runes, c)
+			for j := 0; j < blocks; j++ {
+				b.masks = append(b.masks, 0)
+			}
+		}
+		b.masks[k*blocks+block] |= bit
+	}
+}
+
+// mask return the match mask of c in block, k being the index of c in runes when it is not ASCII.
+func (b *bitParallel) mask(c rune, k, block, blocks int) uint64 {
+	if c < utf8.RuneSelf {
+		return b.peq[block*utf8.RuneSelf+int(c)]
+	}
+	if k < 0 {
+		return 0
+	}
+	return b.masks[k*blocks+block]
+}
+
+func (b *bitParallel) index(c rune) int {
+	for k, r := range b.runes {
+		if r == c {
+			return k
+		}
+	}
+	return -1
+}
+
+// advance process a column of the block given the horizontal delta entering from the
+// block above, and return the horizontal delta leaving its last row.
+func (b *bitParallel) advance(block int, eq uint64, hin int, high uint64) int {
+	pv, mv := b.pv[block], b.mv[block]
+
+	xv := eq | mv
+	if hin < 0 {
+		eq |= 1
+	}
+	xh := (((eq & pv) + pv) ^ pv) | eq
+
+	ph := mv | ^(xh | pv)
+	mh := pv & xh
+
+	hout := 0
+	if ph&high != 0 {
+		hout = 1
+	} else if mh&high != 0 {
+		hout = -1
+	}
+
+	ph <<= 1
+	mh <<= 1
+	if hin < 0 {
+		mh |= 1
+	} else if hin > 0 {
+		ph |= 1
+	}
+
+	b.pv[block] = mh | ^(xv | ph)
+	b.mv[block] = ph & xv
+
+	return hout
+}
+
+func growUints(s []uint64, n int) []uint64 {
+	if cap(s) < n {
+		return make([]uint64, n)
+	}
+	s = s[:n]
+	for i := range s {
+		s[i] = 0
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package strings
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+func TestBitParallelDistance(t *testing.T) {
+	alphabet := []rune("abcdeñáz ")
+	random := rand.New(rand.NewSource(1))
+
+	randomRunes := func(max int) []rune {
+		runes := make([]rune, random.Intn(max+1))
+		for i := range runes {
+			runes[i] = alphabet[random.Intn(len(alphabet))]
+		}
+		return runes
+	}
+
+	var bits bitParallel
+	agrees := func(seed int64) bool {
+		random.Seed(seed)
+		source, target := randomRunes(200), randomRunes(200)
+
+		want := float64(len(source) + len(target))
+		if len(source) > 0 && len(target) > 0 {
+			want = levenshteinRows(source, target, DefaultOptions, make([]float64, len(target)+1), make([]float64, len(target)+1))
+		}
+
+		return float64(bits.distance(source, target)) == want
+	}
+
+	if err := quick.Check(agrees, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBitParallelDistanceBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		target string
+		want   int
+	}{
+		{name: "Empty", source: "", target: "carcasa", want: 7},
+		{name: "Equals", source: "carcasa", target: "carcasa", want: 0},
+		{name: "Substitution", source: "carcasa", target: "carroza", want: 3},
+		{name: "Non ASCII", source: "González", target: "Gonzalez", want: 1},
+		{name: "One block", source: string(repeat('a', 64)), target: string(repeat('a', 63)), want: 1},
+		{name: "Two blocks", source: string(repeat('a', 65)), target: string(repeat('b', 65)), want: 65},
+		{name: "Three blocks", source: string(repeat('a', 130)), target: "b" + string(repeat('a', 128)), want: 2},
+	}
+	var bits bitParallel
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bits.distance([]rune(tt.source), []rune(tt.target)); got != tt.want {
+				t.Errorf("distance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func repeat(r rune, n int) []rune {
+	runes := make([]rune, n)
+	for i := range runes {
+		runes[i] = r
+	}
+	return runes
+}
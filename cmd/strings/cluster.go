@@ -0,0 +1,270 @@
+package strings
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Cluster groups the spelling variants of the same value.
+type Cluster struct {
+	// Canonical is the suggested representative, the most frequent value and
+	// the first seen on ties.
+	Canonical string
+	// Values holds the distinct values of the cluster, the most frequent first.
+	Values []string
+	// Size is the number of values clustered, counting the repeated ones.
+	Size int
+}
+
+// KeyFunc computes the key used by ClusterKeyCollision.
+type KeyFunc func(str string) string
+
+// distinctValues holds the distinct values in order of appearance and how many times they appear.
+type distinctValues struct {
+	values []string
+	counts []int
+}
+
+func distinct(values []string) distinctValues {
+	var d distinctValues
+	index := make(map[string]int, len(values))
+	for _, value := range values {
+		i, ok := index[value]
+		if !ok {
+			i = len(d.values)
+			index[value] = i
+			d.values = append(d.values, value)
+			d.counts = append(d.counts, 0)
+		}
+		d.counts[i]++
+	}
+	return d
+}
+
+// cluster builds a Cluster from the indexes of the distinct values.
+func (d distinctValues) cluster(members []int) Cluster {
+	sort.SliceStable(members, func(i, j int) bool {
+		if d.counts[members[i]] != d.counts[members[j]] {
+			return d.counts[members[i]] > d.counts[members[j]]
+		}
+		return members[i] < members[j]
+	})
+
+	c := Cluster{Canonical: d.values[members[0]]}
+	for _, m := range members {
+		c.Values = append(c.Values, d.values[m])
+		c.Size += d.counts[m]
+	}
+	return c
+}
+
+func sortClusters(clusters []Cluster) []Cluster {
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Size > clusters[j].Size
+	})
+	return clusters
+}
+
+// ClusterSingleLinkage groups the values whose similarity, directly or through a chain of
+// other values, is at least threshold. When metric is nil GetJaroWinklerSimilarity is used.
+func ClusterSingleLinkage(values []string, metric SimilarityFunc, threshold float64) []Cluster {
+	if metric == nil {
+		metric = GetJaroWinklerSimilarity
+	}
+
+	d := distinct(values)
+
+	parent := make([]int, len(d.values))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range d.values {
+		for j := i + 1; j < len(d.values); j++ {
+			if find(i) == find(j) {
+				continue
+			}
+			if metric(d.values[i], d.values[j]) >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := range d.values {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+
+	clusters := make([]Cluster, 0, len(roots))
+	for _, root := range roots {
+		clusters = append(clusters, d.cluster(groups[root]))
+	}
+
+	return sortClusters(clusters)
+}
+
+// ClusterDBSCAN groups the values with DBSCAN, two values being neighbours when their
+// similarity is at least minSimilarity. A value is a core point when it has, counting itself
+// and the repeated values, at least minPoints neighbours. The values that can not be reached
+// from a core point are returned as noise. When metric is nil GetJaroWinklerSimilarity is used.
+func ClusterDBSCAN(values []string, metric SimilarityFunc, minSimilarity float64, minPoints int) (clusters []Cluster, noise []string) {
+	if metric == nil {
+		metric = GetJaroWinklerSimilarity
+	}
+
+	d := distinct(values)
+
+	neighbours := make([][]int, len(d.values))
+	for i := range d.values {
+		neighbours[i] = append(neighbours[i], i)
+		for j := i + 1; j < len(d.values); j++ {
+			if metric(d.values[i], d.values[j]) >= minSimilarity {
+				neighbours[i] = append(neighbours[i], j)
+				neighbours[j] = append(neighbours[j], i)
+			}
+		}
+	}
+
+	isCore := func(i int) bool {
+		points := 0
+		for _, n := range neighbours[i] {
+			points += d.counts[n]
+		}
+		return points >= minPoints
+	}
+
+	const unvisited = -1
+	label := make([]int, len(d.values))
+	for i := range label {
+		label[i] = unvisited
+	}
+
+	var groups [][]int
+	for i := range d.values {
+		if label[i] != unvisited || !isCore(i) {
+			continue
+		}
+
+		id := len(groups)
+		groups = append(groups, nil)
+		queue := []int{i}
+		label[i] = id
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			groups[id] = append(groups[id], p)
+			if !isCore(p) {
+				continue
+			}
+			for _, n := range neighbours[p] {
+				if label[n] == unvisited {
+					label[n] = id
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+
+	for _, group := range groups {
+		clusters = append(clusters, d.cluster(group))
+	}
+	for i, value := range d.values {
+		if label[i] == unvisited {
+			noise = append(noise, value)
+		}
+	}
+
+	return sortClusters(clusters), noise
+}
+
+// ClusterKeyCollision groups the values having the same key, like OpenRefine does. Use
+// Fingerprint or an NGramFingerprint as key. When key is nil Fingerprint is used.
+func ClusterKeyCollision(values []string, key KeyFunc) []Cluster {
+	if key == nil {
+		key = Fingerprint
+	}
+
+	d := distinct(values)
+
+	groups := make(map[string][]int)
+	var keys []string
+	for i, value := range d.values {
+		k := key(value)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], i)
+	}
+
+	clusters := make([]Cluster, 0, len(keys))
+	for _, k := range keys {
+		clusters = append(clusters, d.cluster(groups[k]))
+	}
+
+	return sortClusters(clusters)
+}
+
+// Fingerprint return the OpenRefine fingerprint of str: lowercase, without accents and
+// punctuation, and with its distinct words sorted.
+func Fingerprint(str string) string {
+	return strings.Join(sortedUnique(tokenize(removePunctuation(str))), " ")
+}
+
+// NGramFingerprint return a KeyFunc computing the OpenRefine n-gram fingerprint: the sorted
+// distinct n-grams of str once lowercased and without accents, punctuation and spaces. It panics
+// if n is not positive.
+func NGramFingerprint(n int) KeyFunc {
+	if n < 1 {
+		panic("non-positive n for NGramFingerprint")
+	}
+
+	return func(str string) string {
+		runes := []rune(strings.Join(tokenize(removePunctuation(str)), ""))
+		if len(runes) < n {
+			return string(runes)
+		}
+
+		grams := make([]string, 0, len(runes)-n+1)
+		for i := 0; i+n <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+n]))
+		}
+
+		return strings.Join(sortedUnique(grams), "")
+	}
+}
+
+// sortedUnique sorts strs in place and drops the repeated ones.
+func sortedUnique(strs []string) []string {
+	sort.Strings(strs)
+
+	unique := strs[:0]
+	for i, str := range strs {
+		if i == 0 || str != strs[i-1] {
+			unique = append(unique, str)
+		}
+	}
+	return unique
+}
+
+// removePunctuation deletes the punctuation and control characters, so "Tom's" becomes "Toms".
+func removePunctuation(str string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSymbol(r) || (unicode.IsControl(r) && !unicode.IsSpace(r)) {
+			return -1
+		}
+		return r
+	}, str)
+}
@@ -0,0 +1,136 @@
+package strings_test
+
+import (
+	"golibs/cmd/strings"
+	"reflect"
+	"testing"
+)
+
+var cities = []string{
+	"La Habana",
+	"Habana, La",
+	"la habana",
+	"LA HABANA",
+	"Santiago de Cuba",
+	"Santiago de cuba",
+	"Santiago Cuba",
+	"Matanzas",
+	"Matanza",
+	"Matanzas",
+	"Cienfuegos",
+	"La Havana",
+}
+
+func TestClusterKeyCollision(t *testing.T) {
+	tests := []struct {
+		name string
+		key  strings.KeyFunc
+		want []strings.Cluster
+	}{
+		{
+			name: "Fingerprint",
+			key:  strings.Fingerprint,
+			want: []strings.Cluster{
+				{Canonical: "La Habana", Values: []string{"La Habana", "Habana, La", "la habana", "LA HABANA"}, Size: 4},
+				{Canonical: "Santiago de Cuba", Values: []string{"Santiago de Cuba", "Santiago de cuba"}, Size: 2},
+				{Canonical: "Matanzas", Values: []string{"Matanzas"}, Size: 2},
+				{Canonical: "Santiago Cuba", Values: []string{"Santiago Cuba"}, Size: 1},
+				{Canonical: "Matanza", Values: []string{"Matanza"}, Size: 1},
+				{Canonical: "Cienfuegos", Values: []string{"Cienfuegos"}, Size: 1},
+				{Canonical: "La Havana", Values: []string{"La Havana"}, Size: 1},
+			},
+		},
+		{
+			name: "NGram fingerprint",
+			key:  strings.NGramFingerprint(2),
+			want: []strings.Cluster{
+				{Canonical: "La Habana", Values: []string{"La Habana", "la habana", "LA HABANA"}, Size: 3},
+				{Canonical: "Santiago de Cuba", Values: []string{"Santiago de Cuba", "Santiago de cuba"}, Size: 2},
+				{Canonical: "Matanzas", Values: []string{"Matanzas"}, Size: 2},
+				{Canonical: "Habana, La", Values: []string{"Habana, La"}, Size: 1},
+				{Canonical: "Santiago Cuba", Values: []string{"Santiago Cuba"}, Size: 1},
+				{Canonical: "Matanza", Values: []string{"Matanza"}, Size: 1},
+				{Canonical: "Cienfuegos", Values: []string{"Cienfuegos"}, Size: 1},
+				{Canonical: "La Havana", Values: []string{"La Havana"}, Size: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.ClusterKeyCollision(cities, tt.key); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClusterKeyCollision() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterSingleLinkage(t *testing.T) {
+	want := []strings.Cluster{
+		{Canonical: "La Habana", Values: []string{"La Habana", "la habana", "LA HABANA", "La Havana"}, Size: 4},
+		{Canonical: "Santiago de Cuba", Values: []string{"Santiago de Cuba", "Santiago de cuba", "Santiago Cuba"}, Size: 3},
+		{Canonical: "Matanzas", Values: []string{"Matanzas", "Matanza"}, Size: 3},
+		{Canonical: "Habana, La", Values: []string{"Habana, La"}, Size: 1},
+		{Canonical: "Cienfuegos", Values: []string{"Cienfuegos"}, Size: 1},
+	}
+	if got := strings.ClusterSingleLinkage(cities, nil, 0.9); !reflect.DeepEqual(got, want) {
+		t.Errorf("ClusterSingleLinkage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestClusterDBSCAN(t *testing.T) {
+	want := []strings.Cluster{
+		{Canonical: "La Habana", Values: []string{"La Habana", "la habana", "LA HABANA", "La Havana"}, Size: 4},
+		{Canonical: "Santiago de Cuba", Values: []string{"Santiago de Cuba", "Santiago de cuba", "Santiago Cuba"}, Size: 3},
+		{Canonical: "Matanzas", Values: []string{"Matanzas", "Matanza"}, Size: 3},
+	}
+	wantNoise := []string{"Habana, La", "Cienfuegos"}
+
+	got, noise := strings.ClusterDBSCAN(cities, nil, 0.9, 3)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ClusterDBSCAN() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(noise, wantNoise) {
+		t.Errorf("ClusterDBSCAN() noise = %v, want %v", noise, wantNoise)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+		want string
+	}{
+		{name: "Punctuation and duplicates", str: " Tom's  Cafe, Tom's café ", want: "cafe toms"},
+		{name: "Reordered", str: "Habana, La", want: "habana la"},
+		{name: "Empty", str: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Fingerprint(tt.str); got != tt.want {
+				t.Errorf("Fingerprint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNGramFingerprint(t *testing.T) {
+	if got := strings.NGramFingerprint(1)("Paris"); got != "aiprs" {
+		t.Errorf("NGramFingerprint(1) = %v, want %v", got, "aiprs")
+	}
+	if got := strings.NGramFingerprint(2)("banana"); got != "anbana" {
+		t.Errorf("NGramFingerprint(2) = %v, want %v", got, "anbana")
+	}
+}
+
+func TestNGramFingerprint_NonPositive(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NGramFingerprint(%d) did not panic", n)
+				}
+			}()
+			strings.NGramFingerprint(n)
+		}()
+	}
+}
@@ -0,0 +1,148 @@
+package strings
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// CollatorOptions sets which differences a Collator ignores.
+type CollatorOptions struct {
+	IgnoreCase    bool
+	IgnoreAccents bool
+}
+
+// DefaultCollatorOptions ignores case and accents, like the rest of the package.
+var DefaultCollatorOptions = CollatorOptions{
+	IgnoreCase:    true,
+	IgnoreAccents: true,
+}
+
+// letters holds, per language, the letters that are not a base letter with an accent but a
+// letter on their own, so they are kept when the accents are ignored.
+var letters = map[string]string{
+	"es": "ñ",
+	"gl": "ñ",
+	"tr": "çğıöşü",
+	"az": "çğıöşü",
+	"da": "æøå",
+	"nb": "æøå",
+	"no": "æøå",
+	"sv": "åäö",
+	"fi": "åäö",
+}
+
+// Collator compares, sorts and folds strings following the rules of a language, so the
+// Turkish dotted and dotless i or the Spanish ñ are handled properly.
+//
+// A Collator is not safe for concurrent use.
+type Collator struct {
+	tag      language.Tag
+	options  CollatorOptions
+	collator *collate.Collator
+	caser    cases.Caser
+	letters  string
+	buffer   collate.Buffer
+}
+
+// NewCollator returns a Collator for a BCP 47 locale such as "es", "tr" or "en-US".
+func NewCollator(locale string, options CollatorOptions) (*Collator, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	var collateOptions []collate.Option
+	if options.IgnoreCase {
+		collateOptions = append(collateOptions, collate.IgnoreCase, collate.IgnoreWidth)
+	}
+	if options.IgnoreAccents {
+		collateOptions = append(collateOptions, collate.IgnoreDiacritics)
+	}
+
+	base, _ := tag.Base()
+
+	return &Collator{
+		tag:      tag,
+		options:  options,
+		collator: collate.New(tag, collateOptions...),
+		caser:    cases.Lower(tag),
+		letters:  letters[base.String()],
+	}, nil
+}
+
+// Language return the language tag of the Collator.
+func (c *Collator) Language() string {
+	return c.tag.String()
+}
+
+// Compare return -1, 0 or 1 when a sorts before, equal or after b.
+func (c *Collator) Compare(a, b string) int {
+	return c.collator.CompareString(a, b)
+}
+
+// Equal report whether a and b are the same ignoring the differences set in the options.
+func (c *Collator) Equal(a, b string) bool {
+	return c.Compare(a, b) == 0
+}
+
+// Key return a sort key for str. Keys compare with bytes.Compare as their strings do with
+// Compare, and equal strings have equal keys, so they can be used in maps or indexes.
+func (c *Collator) Key(str string) []byte {
+	c.buffer.Reset()
+	key := c.collator.KeyFromString(&c.buffer, str)
+	return append([]byte(nil), key...)
+}
+
+// Sort sorts the strings in place.
+func (c *Collator) Sort(strs []string) {
+	c.collator.SortStrings(strs)
+}
+
+// Fold normalizes str for the metrics of the package following the language rules: it is
+// lowercased with the language casing and, when ignoring accents, the accents are removed
+// except from the letters the language considers on their own.
+func (c *Collator) Fold(str string) string {
+	if c.options.IgnoreCase {
+		str = c.caser.String(str)
+	}
+	if !c.options.IgnoreAccents {
+		return norm.NFC.String(str)
+	}
+
+	var b strings.Builder
+	for _, r := range norm.NFC.String(str) {
+		if strings.ContainsRune(c.letters, unicode.ToLower(r)) {
+			b.WriteRune(r)
+			continue
+		}
+		for _, d := range norm.NFD.String(string(r)) {
+			if !unicode.Is(unicode.Mn, d) {
+				b.WriteRune(d)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// Similarity is the Collator counterpart of GetSimilarity, comparing the folded strings.
+func (c *Collator) Similarity(source, target string) Match {
+	sourceNorm := trimSpace(c.Fold(source))
+	targetNorm := trimSpace(c.Fold(target))
+
+	levSim := 1 - normalized(sourceNorm, targetNorm, DefaultOptions)
+	jaroWSim := jaroWinklerDistance(sourceNorm, targetNorm)
+
+	return Match{
+		Percentage: Distribution{
+			Levenshtein: levSim,
+			JaroWinkler: jaroWSim,
+			Media:       (levSim + jaroWSim) / 2,
+		},
+	}
+}
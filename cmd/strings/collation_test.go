@@ -0,0 +1,98 @@
+package strings_test
+
+import (
+	"bytes"
+	"golibs/cmd/strings"
+	"reflect"
+	"testing"
+)
+
+func TestCollator_Equal(t *testing.T) {
+	type args struct {
+		locale string
+		a      string
+		b      string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{name: "Turkish dotless i", args: args{locale: "tr", a: "KIZ", b: "kız"}, want: true},
+		{name: "Turkish dotted i", args: args{locale: "tr", a: "KIZ", b: "kiz"}, want: false},
+		{name: "English dotless i", args: args{locale: "en", a: "KIZ", b: "kız"}, want: false},
+		{name: "English dotted i", args: args{locale: "en", a: "KIZ", b: "kiz"}, want: true},
+		{name: "Spanish ñ", args: args{locale: "es", a: "Muñoz", b: "munoz"}, want: false},
+		{name: "Spanish accent", args: args{locale: "es", a: "José", b: "JOSE"}, want: true},
+		{name: "English ñ", args: args{locale: "en", a: "Muñoz", b: "munoz"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := strings.NewCollator(tt.args.locale, strings.DefaultCollatorOptions)
+			if err != nil {
+				t.Fatalf("NewCollator() error = %v", err)
+			}
+			if got := c.Equal(tt.args.a, tt.args.b); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+			if got := bytes.Equal(c.Key(tt.args.a), c.Key(tt.args.b)); got != tt.want {
+				t.Errorf("Key() equal = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollator_Fold(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		str    string
+		want   string
+	}{
+		{name: "Turkish", locale: "tr", str: "İSTANBUL Çağlar KIZ", want: "istanbul çağlar kız"},
+		{name: "Spanish", locale: "es", str: "José Muñoz", want: "jose muñoz"},
+		{name: "English", locale: "en", str: "José Muñoz", want: "jose munoz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := strings.NewCollator(tt.locale, strings.DefaultCollatorOptions)
+			if err != nil {
+				t.Fatalf("NewCollator() error = %v", err)
+			}
+			if got := c.Fold(tt.str); got != tt.want {
+				t.Errorf("Fold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollator_Sort(t *testing.T) {
+	c, err := strings.NewCollator("es", strings.DefaultCollatorOptions)
+	if err != nil {
+		t.Fatalf("NewCollator() error = %v", err)
+	}
+
+	got := []string{"ñu", "oso", "Nada", "nube"}
+	c.Sort(got)
+	if want := []string{"Nada", "nube", "ñu", "oso"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort() = %v, want %v", got, want)
+	}
+}
+
+func TestCollator_Similarity(t *testing.T) {
+	es, _ := strings.NewCollator("es", strings.DefaultCollatorOptions)
+	en, _ := strings.NewCollator("en", strings.DefaultCollatorOptions)
+
+	if got := es.Similarity("Muñoz", "Munoz").Percentage.Levenshtein; got != 0.8333333333333334 {
+		t.Errorf("Similarity() es = %v, want %v", got, 0.8333333333333334)
+	}
+	if got, want := en.Similarity("Muñoz", "Munoz"), strings.GetSimilarity("Muñoz", "Munoz"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Similarity() en = %v, want %v", got, want)
+	}
+}
+
+func TestNewCollator(t *testing.T) {
+	if _, err := strings.NewCollator("not a locale", strings.DefaultCollatorOptions); err == nil {
+		t.Errorf("NewCollator() error = nil, want error")
+	}
+}
@@ -0,0 +1,367 @@
+package strings
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Errors returned when reading a fuzzy index.
+var (
+	ErrFuzzyIndexFormat  = errors.New("invalid fuzzy index file")
+	ErrFuzzyIndexVersion = errors.New("unsupported fuzzy index version")
+	ErrFuzzyIndexSize    = errors.New("fuzzy index exceeds 4GB")
+)
+
+// The fuzzy index file is a trie of the normalized terms written in post order, so every
+// node follows its children and the root is the last one:
+//
+//	header: magic "GLFZ", version uint32
+//	node:   ordinals count uvarint, ordinals uvarint..., children uvarint,
+//	        (label uvarint, child offset uint32)... sorted by label
+//	footer: root offset uint32, terms uint32
+//
+// Integers are little endian. The ordinals are the positions of the terms in the slice the
+// index was built from, several when different terms normalize the same.
+const (
+	fuzzyIndexMagic   = "GLFZ"
+	fuzzyIndexVersion = 1
+	fuzzyHeaderSize   = 8
+	fuzzyFooterSize   = 8
+)
+
+// FuzzyResult is a term found by FuzzyIndex.Search.
+type FuzzyResult struct {
+	Term     string
+	Ordinals []int
+	Distance int
+}
+
+// FuzzyIndex is a read only trie of terms stored in a file, usually memory-mapped, that finds
+// the terms within an edit distance of a query without loading the trie into the heap.
+// Queries can run concurrently.
+type FuzzyIndex struct {
+	data  []byte
+	root  uint32
+	terms int
+	close func() error
+}
+
+type trieNode struct {
+	ordinals []int
+	labels   []rune
+	children []*trieNode
+}
+
+func (n *trieNode) child(label rune) *trieNode {
+	i := sort.Search(len(n.labels), func(i int) bool { return n.labels[i] >= label })
+	if i < len(n.labels) && n.labels[i] == label {
+		return n.children[i]
+	}
+
+	child := &trieNode{}
+	n.labels = append(n.labels, 0)
+	n.children = append(n.children, nil)
+	copy(n.labels[i+1:], n.labels[i:])
+	copy(n.children[i+1:], n.children[i:])
+	n.labels[i] = label
+	n.children[i] = child
+	return child
+}
+
+// normalizeTerm normalizes a term like the rest of the package, keeping single spaces between words.
+func normalizeTerm(term string) string {
+	return strings.Join(tokenize(term), " ")
+}
+
+// WriteFuzzyIndex writes the fuzzy index of terms to w.
+func WriteFuzzyIndex(w io.Writer, terms []string) error {
+	root := &trieNode{}
+	for i, term := range terms {
+		node := root
+		for _, r := range normalizeTerm(term) {
+			node = node.child(r)
+		}
+		node.ordinals = append(node.ordinals, i)
+	}
+
+	buf := bufio.NewWriter(w)
+	writer := &trieWriter{w: buf, offset: fuzzyHeaderSize}
+
+	header := make([]byte, fuzzyHeaderSize)
+	copy(header, fuzzyIndexMagic)
+	binary.LittleEndian.PutUint32(header[4:], fuzzyIndexVersion)
+	if _, err := buf.Write(header); err != nil {
+		return err
+	}
+
+	rootOffset, err := writer.write(root)
+	if err != nil {
+		return err
+	}
+
+	footer := make([]byte, fuzzyFooterSize)
+	binary.LittleEndian.PutUint32(footer, rootOffset)
+	binary.LittleEndian.PutUint32(footer[4:], uint32(len(terms)))
+	if _, err := buf.Write(footer); err != nil {
+		return err
+	}
+
+	return buf.Flush()
+}
+
+type trieWriter struct {
+	w      *bufio.Writer
+	offset uint64
+	buf    []byte
+}
+
+func (tw *trieWriter) write(node *trieNode) (uint32, error) {
+	offsets := make([]uint32, len(node.children))
+	for i, child := range node.children {
+		offset, err := tw.write(child)
+		if err != nil {
+			return 0, err
+		}
+		offsets[i] = offset
+	}
+
+	if tw.offset > math.MaxUint32 {
+		return 0, ErrFuzzyIndexSize
+	}
+	offset := uint32(tw.offset)
+
+	b := tw.buf[:0]
+	b = binary.AppendUvarint(b, uint64(len(node.ordinals)))
+	for _, ordinal := range node.ordinals {
+		b = binary.AppendUvarint(b, uint64(ordinal))
+	}
+	b = binary.AppendUvarint(b, uint64(len(node.children)))
+	for i, label := range node.labels {
+		b = binary.AppendUvarint(b, uint64(label))
+		b = binary.LittleEndian.AppendUint32(b, offsets[i])
+	}
+	tw.buf = b
+
+	n, err := tw.w.Write(b)
+	tw.offset += uint64(n)
+	return offset, err
+}
+
+// BuildFuzzyIndex writes the fuzzy index of terms to the file at path.
+func BuildFuzzyIndex(path string, terms []string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteFuzzyIndex(file, terms); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// OpenFuzzyIndex memory-maps the fuzzy index file at path. Close releases it.
+func OpenFuzzyIndex(path string) (*FuzzyIndex, error) {
+	data, closer, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := NewFuzzyIndex(data)
+	if err != nil {
+		closer()
+		return nil, err
+	}
+	idx.close = closer
+
+	return idx, nil
+}
+
+// NewFuzzyIndex returns a FuzzyIndex reading from data, the content of an index file.
+func NewFuzzyIndex(data []byte) (*FuzzyIndex, error) {
+	if len(data) < fuzzyHeaderSize+fuzzyFooterSize || string(data[:4]) != fuzzyIndexMagic {
+		return nil, ErrFuzzyIndexFormat
+	}
+	if version := binary.LittleEndian.Uint32(data[4:]); version != fuzzyIndexVersion {
+		return nil, ErrFuzzyIndexVersion
+	}
+
+	footer := data[len(data)-fuzzyFooterSize:]
+	root := binary.LittleEndian.Uint32(footer)
+	if root < fuzzyHeaderSize || int(root) >= len(data)-fuzzyFooterSize {
+		return nil, ErrFuzzyIndexFormat
+	}
+
+	return &FuzzyIndex{
+		data:  data[:len(data)-fuzzyFooterSize],
+		root:  root,
+		terms: int(binary.LittleEndian.Uint32(footer[4:])),
+	}, nil
+}
+
+// Close releases the memory-mapped file.
+func (idx *FuzzyIndex) Close() error {
+	if idx.close == nil {
+		return nil
+	}
+	err := idx.close()
+	idx.close = nil
+	idx.data = nil
+	return err
+}
+
+// Len return the number of terms the index was built from.
+func (idx *FuzzyIndex) Len() int {
+	return idx.terms
+}
+
+// indexNode is a decoded view of a node in the file.
+type indexNode struct {
+	offset   uint32
+	ordinals []int
+	children int
+	// edges points to the first child edge in the data.
+	edges int
+}
+
+func (idx *FuzzyIndex) node(offset uint32) (indexNode, error) {
+	node := indexNode{offset: offset}
+	pos := int(offset)
+
+	read := func() (uint64, error) {
+		if pos >= len(idx.data) {
+			return 0, ErrFuzzyIndexFormat
+		}
+		v, n := binary.Uvarint(idx.data[pos:])
+		if n <= 0 {
+			return 0, ErrFuzzyIndexFormat
+		}
+		pos += n
+		return v, nil
+	}
+
+	count, err := read()
+	if err != nil {
+		return node, err
+	}
+	for i := uint64(0); i < count; i++ {
+		ordinal, err := read()
+		if err != nil {
+			return node, err
+		}
+		node.ordinals = append(node.ordinals, int(ordinal))
+	}
+
+	children, err := read()
+	if err != nil {
+		return node, err
+	}
+	node.children = int(children)
+	node.edges = pos
+
+	return node, nil
+}
+
+// edges calls fn for every child of node with its label and offset, stopping when fn return false.
+// The children are written before their parent, so a child offset not below the one of node is
+// corrupt and would make the walks loop forever.
+func (idx *FuzzyIndex) edges(node indexNode, fn func(label rune, offset uint32) bool) error {
+	pos := node.edges
+	for i := 0; i < node.children; i++ {
+		label, n := binary.Uvarint(idx.data[pos:])
+		if n <= 0 || pos+n+4 > len(idx.data) {
+			return ErrFuzzyIndexFormat
+		}
+		pos += n
+		offset := binary.LittleEndian.Uint32(idx.data[pos:])
+		pos += 4
+		if offset < fuzzyHeaderSize || offset >= node.offset {
+			return ErrFuzzyIndexFormat
+		}
+		if !fn(rune(label), offset) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Contains report whether term, once normalized, is in the index.
+func (idx *FuzzyIndex) Contains(term string) (bool, error) {
+	offset := idx.root
+	for _, r := range normalizeTerm(term) {
+		node, err := idx.node(offset)
+		if err != nil {
+			return false, err
+		}
+		found := false
+		err = idx.edges(node, func(label rune, child uint32) bool {
+			if label == r {
+				offset, found = child, true
+			}
+			return label < r
+		})
+		if err != nil || !found {
+			return false, err
+		}
+	}
+
+	node, err := idx.node(offset)
+	return len(node.ordinals) > 0, err
+}
+
+// Search return the terms within Levenshtein distance k of query, both normalized, the
+// closest first and in lexicographic order on ties.
+func (idx *FuzzyIndex) Search(query string, k int) ([]FuzzyResult, error) {
+	return idx.SearchAutomaton(NewLevenshteinAutomaton(normalizeTerm(query), k))
+}
+
+// SearchAutomaton return the terms accepted by automaton, walking the trie only along the
+// prefixes that do not reach its dead state. The order is the one of Search.
+func (idx *FuzzyIndex) SearchAutomaton(automaton *LevenshteinAutomaton) ([]FuzzyResult, error) {
+	var results []FuzzyResult
+	var prefix []rune
+	var search func(offset uint32, state int) error
+	search = func(offset uint32, state int) error {
+		node, err := idx.node(offset)
+		if err != nil {
+			return err
+		}
+
+		if d, ok := automaton.Distance(state); ok && len(node.ordinals) > 0 {
+			results = append(results, FuzzyResult{Term: string(prefix), Ordinals: node.ordinals, Distance: d})
+		}
+
+		var inner error
+		err = idx.edges(node, func(label rune, child uint32) bool {
+			next := automaton.Step(state, label)
+			if next < 0 {
+				return true
+			}
+
+			prefix = append(prefix, label)
+			inner = search(child, next)
+			prefix = prefix[:len(prefix)-1]
+			return inner == nil
+		})
+		if err != nil {
+			return err
+		}
+		return inner
+	}
+
+	if err := search(idx.root, automaton.Start()); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Distance < results[j].Distance
+	})
+
+	return results, nil
+}
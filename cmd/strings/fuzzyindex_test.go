@@ -0,0 +1,163 @@
+package strings_test
+
+import (
+	"bytes"
+	"errors"
+	"golibs/cmd/strings"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var surnames = []string{
+	"González",
+	"Gonzales",
+	"Gonzalez",
+	"Rivero",
+	"Castro",
+	"Cruz",
+	"Gómez",
+	"Mesa",
+	"Silva",
+	"De la Cruz",
+}
+
+func openTestFuzzyIndex(t *testing.T) *strings.FuzzyIndex {
+	path := filepath.Join(t.TempDir(), "surnames.fzi")
+	if err := strings.BuildFuzzyIndex(path, surnames); err != nil {
+		t.Fatalf("BuildFuzzyIndex() error = %v", err)
+	}
+	idx, err := strings.OpenFuzzyIndex(path)
+	if err != nil {
+		t.Fatalf("OpenFuzzyIndex() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := idx.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	})
+	return idx
+}
+
+func TestFuzzyIndex_Search(t *testing.T) {
+	idx := openTestFuzzyIndex(t)
+
+	tests := []struct {
+		name  string
+		query string
+		k     int
+		want  []strings.FuzzyResult
+	}{
+		{
+			name:  "Exact",
+			query: "GONZÁLEZ",
+			k:     0,
+			want: []strings.FuzzyResult{
+				{Term: "gonzalez", Ordinals: []int{0, 2}, Distance: 0},
+			},
+		},
+		{
+			name:  "One edit",
+			query: "gonzales",
+			k:     1,
+			want: []strings.FuzzyResult{
+				{Term: "gonzales", Ordinals: []int{1}, Distance: 0},
+				{Term: "gonzalez", Ordinals: []int{0, 2}, Distance: 1},
+			},
+		},
+		{
+			name:  "Two edits",
+			query: "Cruse",
+			k:     2,
+			want: []strings.FuzzyResult{
+				{Term: "cruz", Ordinals: []int{5}, Distance: 2},
+			},
+		},
+		{
+			name:  "Words",
+			query: "dela cruz",
+			k:     1,
+			want: []strings.FuzzyResult{
+				{Term: "de la cruz", Ordinals: []int{9}, Distance: 1},
+			},
+		},
+		{
+			name:  "Nothing",
+			query: "Fernández",
+			k:     2,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := idx.Search(tt.query, tt.k)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyIndex_Contains(t *testing.T) {
+	idx := openTestFuzzyIndex(t)
+
+	for term, want := range map[string]bool{"Gómez": true, "gomez": true, "Gome": false, "": false} {
+		if got, err := idx.Contains(term); err != nil || got != want {
+			t.Errorf("Contains(%q) = %v, %v, want %v", term, got, err, want)
+		}
+	}
+	if idx.Len() != len(surnames) {
+		t.Errorf("Len() = %v, want %v", idx.Len(), len(surnames))
+	}
+}
+
+func TestNewFuzzyIndex(t *testing.T) {
+	var buf bytes.Buffer
+	if err := strings.WriteFuzzyIndex(&buf, surnames); err != nil {
+		t.Fatalf("WriteFuzzyIndex() error = %v", err)
+	}
+
+	idx, err := strings.NewFuzzyIndex(buf.Bytes())
+	if err != nil {
+		t.Fatalf("NewFuzzyIndex() error = %v", err)
+	}
+	if got, _ := idx.Search("silba", 1); len(got) != 1 || got[0].Term != "silva" {
+		t.Errorf("Search() = %+v, want silva", got)
+	}
+
+	if _, err := strings.NewFuzzyIndex([]byte("not an index")); !errors.Is(err, strings.ErrFuzzyIndexFormat) {
+		t.Errorf("NewFuzzyIndex() error = %v, want %v", err, strings.ErrFuzzyIndexFormat)
+	}
+}
+
+func TestFuzzyIndex_Corrupt(t *testing.T) {
+	header := []byte("GLFZ\x01\x00\x00\x00")
+	tests := []struct {
+		name string
+		node []byte
+	}{
+		// a node without ordinals whose only child, labeled a, is itself
+		{name: "Self reference", node: []byte{0, 1, 'a', 8, 0, 0, 0}},
+		{name: "Child in the header", node: []byte{0, 1, 'a', 2, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := append(append([]byte{}, header...), tt.node...)
+			data = append(data, 8, 0, 0, 0, 1, 0, 0, 0)
+
+			idx, err := strings.NewFuzzyIndex(data)
+			if err != nil {
+				t.Fatalf("NewFuzzyIndex() error = %v", err)
+			}
+			if _, err := idx.Search("aaaa", 2); !errors.Is(err, strings.ErrFuzzyIndexFormat) {
+				t.Errorf("Search() error = %v, want %v", err, strings.ErrFuzzyIndexFormat)
+			}
+			if _, err := idx.Contains("aaaa"); !errors.Is(err, strings.ErrFuzzyIndexFormat) {
+				t.Errorf("Contains() error = %v, want %v", err, strings.ErrFuzzyIndexFormat)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package strings
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/transform"
+
+	"golibs/cmd/internal/textnorm"
+)
+
+// Suggestion is a candidate ranked by IncrementalMatcher.Top.
+type Suggestion struct {
+	Candidate string
+	// Index is the position of the candidate in the slice the matcher was built from.
+	Index int
+	// PrefixDistance is the distance between the query and the closest prefix of the
+	// candidate, Distance the one to the whole candidate.
+	PrefixDistance float64
+	Distance       float64
+	// Similarity is 1 minus the prefix distance over the query length.
+	Similarity float64
+}
+
+// IncrementalMatcher ranks a fixed set of candidates against a query typed character by
+// character. It keeps one row of the Levenshtein matrix per candidate and query character,
+// with the query as source, so appending a character computes a single row per candidate and
+// removing one just drops it. Candidates whose closest prefix is already farther than the
+// maximum distance stop being computed until the query is shortened again.
+//
+// The query and the candidates are normalized like the rest of the package, keeping single
+// spaces between words. An IncrementalMatcher is not safe for concurrent use.
+type IncrementalMatcher struct {
+	options     Options
+	maxDistance float64
+	transformer transform.Transformer
+
+	query []rune
+	// separator is a space typed after the query, added to it with the next character.
+	separator  bool
+	candidates []incrementalCandidate
+}
+
+type incrementalCandidate struct {
+	value  string
+	target []rune
+	// rows holds the rows of the matrix one after the other, the first one for the empty query.
+	rows []float64
+	// dead is the query length at which the candidate exceeded the maximum distance, or -1.
+	dead int
+}
+
+// NewIncrementalMatcher returns an IncrementalMatcher over candidates with an empty query.
+// Candidates farther than maxDistance from the query are not suggested, a negative value
+// disables the limit.
+func NewIncrementalMatcher(candidates []string, maxDistance float64, options Options) *IncrementalMatcher {
+	if maxDistance < 0 {
+		maxDistance = math.Inf(1)
+	}
+
+	m := &IncrementalMatcher{
+		options:     options,
+		maxDistance: maxDistance,
+		transformer: textnorm.NewAccentRemover(),
+		candidates:  make([]incrementalCandidate, len(candidates)),
+	}
+
+	for i, value := range candidates {
+		c := &m.candidates[i]
+		c.value = value
+		c.target = []rune(normalizeTerm(value))
+		c.dead = -1
+		c.rows = make([]float64, len(c.target)+1)
+		for j := range c.rows {
+			c.rows[j] = float64(j) * options.InsCost
+		}
+	}
+
+	return m
+}
+
+// Query return the normalized query.
+func (m *IncrementalMatcher) Query() string {
+	return string(m.query)
+}
+
+// Append adds text to the end of the query. Spaces after the query are kept as a single
+// separator, added to the query with the next character, so the query never ends in a space.
+func (m *IncrementalMatcher) Append(text string) {
+	normalized, _, _ := transform.String(m.transformer, text)
+	for _, r := range strings.ToLower(normalized) {
+		if unicode.IsSpace(r) {
+			m.separator = len(m.query) > 0
+			continue
+		}
+		if m.separator {
+			m.push(' ')
+			m.separator = false
+		}
+		m.push(r)
+	}
+}
+
+// Backspace removes the last n characters of the normalized query, the first one being the
+// pending separator if any.
+func (m *IncrementalMatcher) Backspace(n int) {
+	if m.separator && n > 0 {
+		m.separator = false
+		n--
+	}
+	if n > len(m.query) {
+		n = len(m.query)
+	}
+	m.truncate(len(m.query) - n)
+}
+
+// Set replaces the query, keeping the rows of the prefix it shares with the current one. Like
+// with Append, trailing spaces are kept as the pending separator.
+func (m *IncrementalMatcher) Set(query string) {
+	target := []rune(normalizeTerm(query))
+	trimmed := strings.TrimRightFunc(query, unicode.IsSpace)
+
+	common := 0
+	for common < len(m.query) && common < len(target) && m.query[common] == target[common] {
+		common++
+	}
+	m.truncate(common)
+
+	for _, r := range target[common:] {
+		m.push(r)
+	}
+	m.separator = len(target) > 0 && len(trimmed) < len(query)
+}
+
+// Reset empties the query.
+func (m *IncrementalMatcher) Reset() {
+	m.truncate(0)
+}
+
+func (m *IncrementalMatcher) push(r rune) {
+	m.query = append(m.query, r)
+	i := len(m.query)
+
+	for k := range m.candidates {
+		c := &m.candidates[k]
+		if c.dead >= 0 {
+			continue
+		}
+
+		columns := len(c.target) + 1
+		c.rows = append(c.rows, make([]float64, columns)...)
+		previous := c.rows[(i-1)*columns : i*columns]
+		current := c.rows[i*columns:]
+
+		current[0] = float64(i) * m.options.DelCost
+		best := current[0]
+		for j := 1; j < columns; j++ {
+			substitutionOrEqual := previous[j-1]
+			if r != c.target[j-1] {
+				substitutionOrEqual += m.options.SubCost
+			}
+			current[j] = math.Min(previous[j]+m.options.DelCost, math.Min(current[j-1]+m.options.InsCost, substitutionOrEqual))
+			best = math.Min(best, current[j])
+		}
+
+		if best > m.maxDistance {
+			c.dead = i
+		}
+	}
+}
+
+func (m *IncrementalMatcher) truncate(length int) {
+	m.query = m.query[:length]
+	m.separator = false
+
+	for k := range m.candidates {
+		c := &m.candidates[k]
+		columns := len(c.target) + 1
+		if c.dead > length {
+			c.dead = -1
+		}
+		if rows := (length + 1) * columns; c.dead < 0 && len(c.rows) > rows {
+			c.rows = c.rows[:rows]
+		}
+	}
+}
+
+// Top return the n best candidates for the current query, ordered by prefix distance, then by
+// distance to the whole candidate and then by their position. A negative n return all of them.
+func (m *IncrementalMatcher) Top(n int) []Suggestion {
+	var suggestions []Suggestion
+
+	i := len(m.query)
+	for k := range m.candidates {
+		c := &m.candidates[k]
+		if c.dead >= 0 {
+			continue
+		}
+
+		columns := len(c.target) + 1
+		row := c.rows[i*columns : (i+1)*columns]
+
+		prefix := row[0]
+		for _, d := range row[1:] {
+			prefix = math.Min(prefix, d)
+		}
+
+		similarity := 1.0
+		if i > 0 {
+			similarity = math.Max(0, 1-prefix/float64(i))
+		}
+
+		suggestions = append(suggestions, Suggestion{
+			Candidate:      c.value,
+			Index:          k,
+			PrefixDistance: prefix,
+			Distance:       row[columns-1],
+			Similarity:     similarity,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(a, b int) bool {
+		if suggestions[a].PrefixDistance != suggestions[b].PrefixDistance {
+			return suggestions[a].PrefixDistance < suggestions[b].PrefixDistance
+		}
+		return suggestions[a].Distance < suggestions[b].Distance
+	})
+
+	if n >= 0 && n < len(suggestions) {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
@@ -0,0 +1,126 @@
+package strings_test
+
+import (
+	"golibs/cmd/strings"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var provinces = []string{
+	"Madrid",
+	"Málaga",
+	"Mallorca",
+	"Murcia",
+	"Santa Cruz de Tenerife",
+	"Las Palmas de Gran Canaria",
+	"Santander",
+	"Salamanca",
+}
+
+func candidates(suggestions []strings.Suggestion) []string {
+	values := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		values[i] = s.Candidate
+	}
+	return values
+}
+
+func TestIncrementalMatcher_Top(t *testing.T) {
+	m := strings.NewIncrementalMatcher(provinces, 1, strings.DefaultOptions)
+
+	m.Append("MA")
+	if got := candidates(m.Top(3)); len(got) != 3 || got[0] != "Madrid" || got[1] != "Málaga" || got[2] != "Mallorca" {
+		t.Errorf("Top(3) after %q = %v", m.Query(), got)
+	}
+
+	m.Append("la")
+	got := m.Top(-1)
+	if len(got) == 0 || got[0].Candidate != "Málaga" || got[0].PrefixDistance != 0 || got[0].Distance != 2 {
+		t.Fatalf("Top(-1) after %q = %+v", m.Query(), got)
+	}
+	if got[0].Similarity != 1 {
+		t.Errorf("Similarity = %v, want 1", got[0].Similarity)
+	}
+
+	m.Append("ca")
+	if got := candidates(m.Top(1)); len(got) != 1 || got[0] != "Málaga" {
+		t.Errorf("Top(1) after %q = %v", m.Query(), got)
+	}
+
+	m.Backspace(3)
+	if got := candidates(m.Top(3)); len(got) != 3 || got[0] != "Málaga" || got[1] != "Mallorca" || got[2] != "Madrid" {
+		t.Errorf("Top(3) after %q = %v", m.Query(), got)
+	}
+
+	m.Set("santa  cruz")
+	if got := candidates(m.Top(1)); m.Query() != "santa cruz" || len(got) != 1 || got[0] != "Santa Cruz de Tenerife" {
+		t.Errorf("Top(1) after %q = %v", m.Query(), got)
+	}
+
+	m.Reset()
+	if got := m.Top(-1); m.Query() != "" || len(got) != len(provinces) {
+		t.Errorf("Top(-1) after Reset() = %d suggestions, want %d", len(got), len(provinces))
+	}
+}
+
+func TestIncrementalMatcher_SetAndAppend(t *testing.T) {
+	for _, query := range []string{"santa ", "  santa   cruz ", "santa\t", " "} {
+		set := strings.NewIncrementalMatcher(provinces, 2, strings.DefaultOptions)
+		set.Set(query)
+		appended := strings.NewIncrementalMatcher(provinces, 2, strings.DefaultOptions)
+		appended.Append(query)
+		if set.Query() != appended.Query() || !reflect.DeepEqual(set.Top(-1), appended.Top(-1)) {
+			t.Errorf("Set(%q) gives %q, Append(%q) gives %q", query, set.Query(), query, appended.Query())
+		}
+
+		// the trailing space separates the next word in both
+		set.Append("de")
+		appended.Append("de")
+		if set.Query() != appended.Query() {
+			t.Errorf("Append(%q) after Set(%q) gives %q, want %q", "de", query, set.Query(), appended.Query())
+		}
+	}
+}
+
+// TestIncrementalMatcher_Consistency checks that a random sequence of edits gives the same
+// distances as a matcher built for the final query.
+func TestIncrementalMatcher_Consistency(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "e", "l", "m", "s", "n", "r", " "}
+
+	options := strings.Options{InsCost: 1, DelCost: 2, SubCost: 1.5}
+	m := strings.NewIncrementalMatcher(provinces, 3, options)
+	for i := 0; i < 500; i++ {
+		if random.Intn(3) == 0 {
+			m.Backspace(random.Intn(3))
+		} else {
+			m.Append(alphabet[random.Intn(len(alphabet))])
+		}
+
+		fresh := strings.NewIncrementalMatcher(provinces, 3, options)
+		fresh.Append(m.Query())
+
+		got, want := m.Top(-1), fresh.Top(-1)
+		if len(got) != len(want) {
+			t.Fatalf("query %q: %d suggestions, want %d", m.Query(), len(got), len(want))
+		}
+		for j := range got {
+			if got[j] != want[j] {
+				t.Fatalf("query %q: suggestion %d = %+v, want %+v", m.Query(), j, got[j], want[j])
+			}
+		}
+	}
+}
+
+func BenchmarkIncrementalMatcher_Append(b *testing.B) {
+	dictionary := benchmarkDictionary()
+	m := strings.NewIncrementalMatcher(dictionary, 2, strings.DefaultOptions)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Set("gonz")
+		m.Append("a")
+		m.Top(10)
+		m.Backspace(1)
+	}
+}
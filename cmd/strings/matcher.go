@@ -0,0 +1,171 @@
+package strings
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+
+	"golibs/cmd/internal/textnorm"
+)
+
+// Matcher computes the same similarities as GetSimilarity, GetLevenshteinSimilarity and
+// GetJaroWinklerSimilarity reusing its buffers between comparisons, so once warmed up
+// it does not allocate for ASCII input.
+//
+// A Matcher is not safe for concurrent use, but it can be kept in a sync.Pool.
+type Matcher struct {
+	options     Options
+	transformer transform.Transformer
+
+	raw            []byte
+	transformed    []byte
+	source, target []byte
+
+	sourceRunes, targetRunes []rune
+	previous, current        []float64
+	bits                     bitParallel
+	sourceMatches            []bool
+	targetMatches            []bool
+}
+
+// NewMatcher returns a Matcher using options for the Levenshtein costs.
+func NewMatcher(options Options) *Matcher {
+	return &Matcher{
+		options:     options,
+		transformer: textnorm.NewAccentRemover(),
+	}
+}
+
+// Similarity is the Matcher counterpart of GetSimilarity.
+func (m *Matcher) Similarity(source, target string) Match {
+	m.normalize(source, target)
+
+	levSim := m.levenshtein()
+	jaroWSim := m.jaroWinkler()
+
+	return Match{
+		Percentage: Distribution{
+			Levenshtein: levSim,
+			JaroWinkler: jaroWSim,
+			Media:       (levSim + jaroWSim) / 2,
+		},
+	}
+}
+
+// Levenshtein is the Matcher counterpart of GetLevenshteinSimilarity.
+func (m *Matcher) Levenshtein(source, target string) float64 {
+	m.normalize(source, target)
+
+	return m.levenshtein()
+}
+
+// JaroWinkler is the Matcher counterpart of GetJaroWinklerSimilarity.
+func (m *Matcher) JaroWinkler(source, target string) float64 {
+	m.normalize(source, target)
+
+	return m.jaroWinkler()
+}
+
+func (m *Matcher) levenshtein() float64 {
+	m.sourceRunes = appendRunes(m.sourceRunes[:0], m.source)
+	m.targetRunes = appendRunes(m.targetRunes[:0], m.target)
+
+	var d float64
+	switch {
+	case len(m.sourceRunes) == 0 || len(m.targetRunes) == 0:
+	case m.options.unitCosts():
+		d = float64(m.bits.distance(m.sourceRunes, m.targetRunes))
+	default:
+		m.previous = growFloats(m.previous, len(m.targetRunes)+1)
+		m.current = growFloats(m.current, len(m.targetRunes)+1)
+		d = levenshteinRows(m.sourceRunes, m.targetRunes, m.options, m.previous, m.current)
+	}
+
+	return 1 - normalizedDistance(d, len(m.source), len(m.target))
+}
+
+func (m *Matcher) jaroWinkler() float64 {
+	if len(m.source) == 0 || len(m.target) == 0 {
+		return 0
+	}
+
+	if bytes.EqualFold(m.source, m.target) {
+		return 1
+	}
+
+	m.sourceMatches = growBools(m.sourceMatches, len(m.source))
+	m.targetMatches = growBools(m.targetMatches, len(m.target))
+
+	return jaroWinkler(m.source, m.target, m.sourceMatches, m.targetMatches)
+}
+
+// normalize applies strNormalization to both strings storing the result in the Matcher buffers.
+func (m *Matcher) normalize(source, target string) {
+	m.source = m.normalizeInto(m.source[:0], source)
+	m.target = m.normalizeInto(m.target[:0], target)
+}
+
+func (m *Matcher) normalizeInto(dst []byte, str string) []byte {
+	if isASCII(str) {
+		for i := 0; i < len(str); i++ {
+			c := str[i]
+			if unicode.IsSpace(rune(c)) {
+				continue
+			}
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			dst = append(dst, c)
+		}
+		return dst
+	}
+
+	m.raw = append(m.raw[:0], str...)
+	m.transformed, _, _ = transform.Append(m.transformer, m.transformed[:0], m.raw)
+
+	for i := 0; i < len(m.transformed); {
+		r, size := utf8.DecodeRune(m.transformed[i:])
+		i += size
+		if unicode.IsSpace(r) {
+			continue
+		}
+		dst = utf8.AppendRune(dst, unicode.ToLower(r))
+	}
+	return dst
+}
+
+func isASCII(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func appendRunes(dst []rune, str []byte) []rune {
+	for _, r := range string(str) {
+		dst = append(dst, r)
+	}
+	return dst
+}
+
+func growFloats(s []float64, n int) []float64 {
+	if cap(s) < n {
+		return make([]float64, n)
+	}
+	return s[:n]
+}
+
+func growBools(s []bool, n int) []bool {
+	if cap(s) < n {
+		return make([]bool, n)
+	}
+	s = s[:n]
+	for i := range s {
+		s[i] = false
+	}
+	return s
+}
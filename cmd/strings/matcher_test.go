@@ -0,0 +1,109 @@
+package strings_test
+
+import (
+	"golibs/cmd/strings"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var matcherPairs = []struct {
+	source string
+	target string
+}{
+	{source: "carcasa", target: "carroza"},
+	{source: "carcasa", target: "carcas"},
+	{source: "ab", target: "abc"},
+	{source: "Reynier Gonzalez", target: "reyNier González"},
+	{source: "Gonzalez Reynier", target: "reyNier González"},
+	{source: "Reynier Gonzalez Cruz", target: "González Reynier    "},
+	{source: "Arelys RIVERO CASTRO", target: "Reynier González Cruz"},
+	{source: "MARTHA Mesa Silva", target: "MARTA Mesa"},
+	{source: "Ñandú Muñoz", target: "nandu munoz"},
+	{source: "", target: "Asheville"},
+}
+
+func TestMatcher(t *testing.T) {
+	options := strings.Options{
+		InsCost: 1.25,
+		DelCost: 1,
+		SubCost: 1.5,
+	}
+	matcher := strings.NewMatcher(options)
+
+	for _, tt := range matcherPairs {
+		t.Run(tt.source+"/"+tt.target, func(t *testing.T) {
+			if got, want := matcher.Levenshtein(tt.source, tt.target), strings.GetLevenshteinSimilarity(tt.source, tt.target, options); got != want {
+				t.Errorf("Levenshtein() = %v, want %v", got, want)
+			}
+			if got, want := matcher.JaroWinkler(tt.source, tt.target), strings.GetJaroWinklerSimilarity(tt.source, tt.target); got != want {
+				t.Errorf("JaroWinkler() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMatcher_Similarity(t *testing.T) {
+	matcher := strings.NewMatcher(strings.DefaultOptions)
+
+	for _, tt := range matcherPairs {
+		t.Run(tt.source+"/"+tt.target, func(t *testing.T) {
+			if got, want := matcher.Similarity(tt.source, tt.target), strings.GetSimilarity(tt.source, tt.target); !reflect.DeepEqual(got, want) {
+				t.Errorf("Similarity() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMatcher_Allocations(t *testing.T) {
+	matcher := strings.NewMatcher(strings.DefaultOptions)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		matcher.Similarity("Arelys RIVERO CASTRO", "Reynier Gonzalez Cruz")
+	})
+	if allocs != 0 {
+		t.Errorf("Similarity() allocations = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkGetSimilarity(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		strings.GetSimilarity("Arelys RIVERO CASTRO", "Reynier Gonzalez Cruz")
+	}
+}
+
+func BenchmarkMatcher_Similarity(b *testing.B) {
+	matcher := strings.NewMatcher(strings.DefaultOptions)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		matcher.Similarity("Arelys RIVERO CASTRO", "Reynier Gonzalez Cruz")
+	}
+}
+
+func BenchmarkMatcher_SimilarityNonASCII(b *testing.B) {
+	matcher := strings.NewMatcher(strings.DefaultOptions)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		matcher.Similarity("Reynier González Cruz", "Reynier Gonzalez Cruz")
+	}
+}
+
+func BenchmarkMatcher_Pool(b *testing.B) {
+	pool := sync.Pool{
+		New: func() any {
+			return strings.NewMatcher(strings.DefaultOptions)
+		},
+	}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			matcher := pool.Get().(*strings.Matcher)
+			matcher.Similarity("Arelys RIVERO CASTRO", "Reynier Gonzalez Cruz")
+			pool.Put(matcher)
+		}
+	})
+}
@@ -0,0 +1,391 @@
+package strings
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Errors returned by the LSH index.
+var (
+	ErrInvalidBands   = errors.New("the number of hashes must be a multiple of the number of bands")
+	ErrIndexVersion   = errors.New("unsupported LSH index version")
+	ErrSignatureSize  = errors.New("signature size does not match the index")
+	ErrDuplicatedItem = errors.New("item already in the index")
+	ErrIndexFormat    = errors.New("invalid LSH index file")
+)
+
+const lshIndexVersion = 1
+
+// maxIndexHashes and maxIndexShingle bound the parameters read from an index file, so a
+// corrupt one cannot make ReadLSHIndex allocate without limit.
+const (
+	maxIndexHashes  = 1 << 16
+	maxIndexShingle = 1 << 10
+)
+
+// MinHasher computes MinHash signatures of the character shingles of a text, whose
+// agreement estimates the Jaccard similarity of the shingle sets.
+type MinHasher struct {
+	hashes  int
+	shingle int
+	seed    int64
+	salts   []uint64
+}
+
+// Signature is the MinHash signature of a text.
+type Signature []uint64
+
+// NewMinHasher returns a MinHasher with hashes permutations over shingles of shingle runes.
+// Signatures are only comparable when computed with the same parameters.
+func NewMinHasher(hashes, shingle int, seed int64) *MinHasher {
+	if hashes < 1 {
+		hashes = 1
+	}
+	if shingle < 1 {
+		shingle = 1
+	}
+
+	random := rand.New(rand.NewSource(seed))
+	salts := make([]uint64, hashes)
+	for i := range salts {
+		salts[i] = random.Uint64()
+	}
+
+	return &MinHasher{
+		hashes:  hashes,
+		shingle: shingle,
+		seed:    seed,
+		salts:   salts,
+	}
+}
+
+// Signature return the signature of text, normalized like the rest of the package.
+func (h *MinHasher) Signature(text string) Signature {
+	signature := make(Signature, h.hashes)
+	for i := range signature {
+		signature[i] = math.MaxUint64
+	}
+
+	for _, shingle := range h.shingles(text) {
+		for i, salt := range h.salts {
+			if v := mix64(shingle ^ salt); v < signature[i] {
+				signature[i] = v
+			}
+		}
+	}
+
+	return signature
+}
+
+// shingles return the hashes of the distinct shingles of the normalized text.
+func (h *MinHasher) shingles(text string) []uint64 {
+	runes := []rune(strings.Join(tokenize(text), " "))
+	if len(runes) == 0 {
+		return nil
+	}
+
+	size := h.shingle
+	if len(runes) < size {
+		size = len(runes)
+	}
+
+	seen := make(map[uint64]bool)
+	var hashes []uint64
+	for i := 0; i+size <= len(runes); i++ {
+		hasher := fnv.New64a()
+		_, _ = hasher.Write([]byte(string(runes[i : i+size])))
+		sum := hasher.Sum64()
+		if !seen[sum] {
+			seen[sum] = true
+			hashes = append(hashes, sum)
+		}
+	}
+	return hashes
+}
+
+// Jaccard return the Jaccard similarity estimated from two signatures of the same size.
+func (s Signature) Jaccard(other Signature) float64 {
+	if len(s) == 0 || len(s) != len(other) {
+		return 0
+	}
+
+	equal := 0
+	for i := range s {
+		if s[i] == other[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(s))
+}
+
+// mix64 is the splitmix64 finalizer, used to derive a hash permutation from every salt.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// LSHIndex finds near duplicate texts splitting their MinHash signatures in bands, two texts
+// being candidates when all the rows of any band agree.
+//
+// An LSHIndex is not safe for concurrent use.
+type LSHIndex struct {
+	hasher     *MinHasher
+	bands      int
+	rows       int
+	buckets    []map[uint64][]string
+	signatures map[string]Signature
+}
+
+// Neighbour is a result of LSHIndex.Query.
+type Neighbour struct {
+	ID         string
+	Similarity float64
+}
+
+// NewLSHIndex returns an empty index splitting the signatures of hasher in bands.
+func NewLSHIndex(hasher *MinHasher, bands int) (*LSHIndex, error) {
+	if bands < 1 || hasher.hashes%bands != 0 {
+		return nil, ErrInvalidBands
+	}
+
+	idx := &LSHIndex{
+		hasher:     hasher,
+		bands:      bands,
+		rows:       hasher.hashes / bands,
+		buckets:    make([]map[uint64][]string, bands),
+		signatures: make(map[string]Signature),
+	}
+	for i := range idx.buckets {
+		idx.buckets[i] = make(map[uint64][]string)
+	}
+
+	return idx, nil
+}
+
+// Len return the number of items in the index.
+func (idx *LSHIndex) Len() int {
+	return len(idx.signatures)
+}
+
+// Insert adds the text under id.
+func (idx *LSHIndex) Insert(id, text string) error {
+	return idx.InsertSignature(id, idx.hasher.Signature(text))
+}
+
+// InsertSignature adds an already computed signature under id.
+func (idx *LSHIndex) InsertSignature(id string, signature Signature) error {
+	if len(signature) != idx.hasher.hashes {
+		return ErrSignatureSize
+	}
+	if _, ok := idx.signatures[id]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicatedItem, id)
+	}
+
+	idx.signatures[id] = signature
+	for band := 0; band < idx.bands; band++ {
+		key := idx.bandKey(signature, band)
+		idx.buckets[band][key] = append(idx.buckets[band][key], id)
+	}
+
+	return nil
+}
+
+// Remove deletes id from the index, reporting whether it was there.
+func (idx *LSHIndex) Remove(id string) bool {
+	signature, ok := idx.signatures[id]
+	if !ok {
+		return false
+	}
+
+	delete(idx.signatures, id)
+	for band := 0; band < idx.bands; band++ {
+		key := idx.bandKey(signature, band)
+		ids := idx.buckets[band][key]
+		for i := range ids {
+			if ids[i] == id {
+				ids = append(ids[:i], ids[i+1:]...)
+				break
+			}
+		}
+		if len(ids) == 0 {
+			delete(idx.buckets[band], key)
+		} else {
+			idx.buckets[band][key] = ids
+		}
+	}
+
+	return true
+}
+
+// Query return the items whose estimated Jaccard similarity with text is at least
+// threshold, the most similar first.
+func (idx *LSHIndex) Query(text string, threshold float64) []Neighbour {
+	return idx.QuerySignature(idx.hasher.Signature(text), threshold)
+}
+
+// QuerySignature is like Query for an already computed signature.
+func (idx *LSHIndex) QuerySignature(signature Signature, threshold float64) []Neighbour {
+	if len(signature) != idx.hasher.hashes {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var neighbours []Neighbour
+	for band := 0; band < idx.bands; band++ {
+		for _, id := range idx.buckets[band][idx.bandKey(signature, band)] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if sim := signature.Jaccard(idx.signatures[id]); sim >= threshold {
+				neighbours = append(neighbours, Neighbour{ID: id, Similarity: sim})
+			}
+		}
+	}
+
+	sort.Slice(neighbours, func(i, j int) bool {
+		if neighbours[i].Similarity != neighbours[j].Similarity {
+			return neighbours[i].Similarity > neighbours[j].Similarity
+		}
+		return neighbours[i].ID < neighbours[j].ID
+	})
+
+	return neighbours
+}
+
+func (idx *LSHIndex) bandKey(signature Signature, band int) uint64 {
+	hasher := fnv.New64a()
+	var buf [8]byte
+	for _, v := range signature[band*idx.rows : (band+1)*idx.rows] {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		_, _ = hasher.Write(buf[:])
+	}
+	return hasher.Sum64()
+}
+
+// lshSnapshot is the serialized form of an LSHIndex. The buckets are rebuilt on load.
+type lshSnapshot struct {
+	Version    int
+	Hashes     int
+	Shingle    int
+	Seed       int64
+	Bands      int
+	IDs        []string
+	Signatures []Signature
+}
+
+// WriteTo serializes the index to w.
+func (idx *LSHIndex) WriteTo(w io.Writer) (int64, error) {
+	snapshot := lshSnapshot{
+		Version: lshIndexVersion,
+		Hashes:  idx.hasher.hashes,
+		Shingle: idx.hasher.shingle,
+		Seed:    idx.hasher.seed,
+		Bands:   idx.bands,
+	}
+	for id := range idx.signatures {
+		snapshot.IDs = append(snapshot.IDs, id)
+	}
+	sort.Strings(snapshot.IDs)
+	for _, id := range snapshot.IDs {
+		snapshot.Signatures = append(snapshot.Signatures, idx.signatures[id])
+	}
+
+	counter := &countingWriter{w: w}
+	err := gob.NewEncoder(counter).Encode(snapshot)
+	return counter.n, err
+}
+
+// ReadLSHIndex loads an index serialized with WriteTo.
+func ReadLSHIndex(r io.Reader) (*LSHIndex, error) {
+	var snapshot lshSnapshot
+	if err := gob.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version != lshIndexVersion {
+		return nil, fmt.Errorf("%w: %d", ErrIndexVersion, snapshot.Version)
+	}
+	if err := snapshot.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIndexFormat, err)
+	}
+
+	idx, err := NewLSHIndex(NewMinHasher(snapshot.Hashes, snapshot.Shingle, snapshot.Seed), snapshot.Bands)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range snapshot.IDs {
+		if err := idx.InsertSignature(id, snapshot.Signatures[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return idx, nil
+}
+
+// validate checks the parameters and the signatures of the snapshot before they are used.
+func (s *lshSnapshot) validate() error {
+	switch {
+	case s.Hashes < 1 || s.Hashes > maxIndexHashes:
+		return fmt.Errorf("%d hashes out of range", s.Hashes)
+	case s.Shingle < 1 || s.Shingle > maxIndexShingle:
+		return fmt.Errorf("shingle of %d runes out of range", s.Shingle)
+	case s.Bands < 1 || s.Bands > s.Hashes || s.Hashes%s.Bands != 0:
+		return fmt.Errorf("%d bands for %d hashes", s.Bands, s.Hashes)
+	case len(s.IDs) != len(s.Signatures):
+		return fmt.Errorf("%d ids for %d signatures", len(s.IDs), len(s.Signatures))
+	}
+	for i, signature := range s.Signatures {
+		if len(signature) != s.Hashes {
+			return fmt.Errorf("signature of %q has %d hashes, want %d", s.IDs[i], len(signature), s.Hashes)
+		}
+	}
+	return nil
+}
+
+// Save writes the index to the file at path.
+func (idx *LSHIndex) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := idx.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadLSHIndex reads an index written with Save.
+func LoadLSHIndex(path string) (*LSHIndex, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadLSHIndex(file)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
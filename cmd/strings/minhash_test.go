@@ -0,0 +1,164 @@
+package strings_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"golibs/cmd/strings"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var descriptions = map[string]string{
+	"a": "Laptop Lenovo ThinkPad T14 Gen 2, 16GB RAM, 512GB SSD, Intel Core i7",
+	"b": "Lenovo ThinkPad T14 Gen 2 laptop with 16GB RAM and 512GB SSD, Intel Core i7",
+	"c": "Laptop Lenovo ThinkPad T14 Gen 2, 16GB RAM, 512GB SSD, Intel Core i5",
+	"d": "Silla de oficina ergonómica con soporte lumbar y reposabrazos ajustables",
+	"e": "Silla de oficina ergonomica con soporte lumbar y reposabrazos regulables",
+}
+
+func newTestIndex(t *testing.T) *strings.LSHIndex {
+	idx, err := strings.NewLSHIndex(strings.NewMinHasher(128, 4, 1), 32)
+	if err != nil {
+		t.Fatalf("NewLSHIndex() error = %v", err)
+	}
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		if err := idx.Insert(id, descriptions[id]); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+	return idx
+}
+
+func ids(neighbours []strings.Neighbour) []string {
+	var result []string
+	for _, n := range neighbours {
+		result = append(result, n.ID)
+	}
+	return result
+}
+
+func TestSignature_Jaccard(t *testing.T) {
+	hasher := strings.NewMinHasher(256, 4, 1)
+
+	same := hasher.Signature(descriptions["a"]).Jaccard(hasher.Signature("LAPTOP lenovo thinkpad t14 gen 2, 16gb ram, 512gb ssd, intel core i7"))
+	if same != 1 {
+		t.Errorf("Jaccard() = %v, want 1", same)
+	}
+
+	near := hasher.Signature(descriptions["a"]).Jaccard(hasher.Signature(descriptions["c"]))
+	far := hasher.Signature(descriptions["a"]).Jaccard(hasher.Signature(descriptions["d"]))
+	if near < 0.8 || far > 0.2 {
+		t.Errorf("Jaccard() near = %v, far = %v", near, far)
+	}
+}
+
+func TestLSHIndex_Query(t *testing.T) {
+	idx := newTestIndex(t)
+
+	tests := []struct {
+		name      string
+		text      string
+		threshold float64
+		want      []string
+	}{
+		{name: "Near duplicates", text: descriptions["a"], threshold: 0.7, want: []string{"a", "c"}},
+		{name: "Accents", text: descriptions["d"], threshold: 0.7, want: []string{"d", "e"}},
+		{name: "Nothing similar", text: "Monitor Dell 27 pulgadas", threshold: 0.5, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(idx.Query(tt.text, tt.threshold)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLSHIndex_InsertRemove(t *testing.T) {
+	idx := newTestIndex(t)
+
+	if err := idx.Insert("a", descriptions["a"]); !errors.Is(err, strings.ErrDuplicatedItem) {
+		t.Errorf("Insert() error = %v, want %v", err, strings.ErrDuplicatedItem)
+	}
+	if !idx.Remove("c") || idx.Remove("c") {
+		t.Errorf("Remove() should only succeed once")
+	}
+	if got := ids(idx.Query(descriptions["a"], 0.7)); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Query() = %v, want %v", got, []string{"a"})
+	}
+	if idx.Len() != 4 {
+		t.Errorf("Len() = %v, want %v", idx.Len(), 4)
+	}
+}
+
+func TestLSHIndex_Serialization(t *testing.T) {
+	idx := newTestIndex(t)
+
+	var buf bytes.Buffer
+	if _, err := idx.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	loaded, err := strings.ReadLSHIndex(&buf)
+	if err != nil {
+		t.Fatalf("ReadLSHIndex() error = %v", err)
+	}
+	if got, want := loaded.Query(descriptions["b"], 0.3), idx.Query(descriptions["b"], 0.3); !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() after ReadLSHIndex = %v, want %v", got, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.lsh")
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err = strings.LoadLSHIndex(path)
+	if err != nil {
+		t.Fatalf("LoadLSHIndex() error = %v", err)
+	}
+	if loaded.Len() != idx.Len() {
+		t.Errorf("Len() after LoadLSHIndex = %v, want %v", loaded.Len(), idx.Len())
+	}
+}
+
+func TestReadLSHIndex_Corrupt(t *testing.T) {
+	// the fields of the serialized index, gob matches them by name
+	type snapshot struct {
+		Version    int
+		Hashes     int
+		Shingle    int
+		Seed       int64
+		Bands      int
+		IDs        []string
+		Signatures [][]uint64
+	}
+
+	tests := []struct {
+		name     string
+		snapshot snapshot
+	}{
+		{name: "Truncated signatures", snapshot: snapshot{Version: 1, Hashes: 2, Shingle: 3, Bands: 1, IDs: []string{"a", "b"}, Signatures: [][]uint64{{1, 2}}}},
+		{name: "Short signature", snapshot: snapshot{Version: 1, Hashes: 2, Shingle: 3, Bands: 1, IDs: []string{"a"}, Signatures: [][]uint64{{1}}}},
+		{name: "Too many hashes", snapshot: snapshot{Version: 1, Hashes: 1 << 40, Shingle: 3, Bands: 1}},
+		{name: "Negative hashes", snapshot: snapshot{Version: 1, Hashes: -1, Shingle: 3, Bands: 1}},
+		{name: "Invalid shingle", snapshot: snapshot{Version: 1, Hashes: 2, Shingle: 0, Bands: 1}},
+		{name: "Invalid bands", snapshot: snapshot{Version: 1, Hashes: 4, Shingle: 3, Bands: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(tt.snapshot); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := strings.ReadLSHIndex(&buf); !errors.Is(err, strings.ErrIndexFormat) {
+				t.Errorf("ReadLSHIndex() error = %v, want %v", err, strings.ErrIndexFormat)
+			}
+		})
+	}
+}
+
+func TestNewLSHIndex(t *testing.T) {
+	if _, err := strings.NewLSHIndex(strings.NewMinHasher(100, 4, 1), 30); !errors.Is(err, strings.ErrInvalidBands) {
+		t.Errorf("NewLSHIndex() error = %v, want %v", err, strings.ErrInvalidBands)
+	}
+}
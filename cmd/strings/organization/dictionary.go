@@ -0,0 +1,84 @@
+package organization
+
+import "strings"
+
+// LegalSuffix describes a legal form of company. Forms of the same Family, such as
+// "S.A." and "S.A. de C.V.", are not considered a mismatch.
+type LegalSuffix struct {
+	Canonical string
+	Family    string
+	// Countries are the ISO 3166-1 alpha-2 codes of the countries using the form.
+	Countries []string
+	// Forms are the normalized spellings, without dots and accents.
+	Forms []string
+}
+
+// usedIn report whether the suffix is used in country, any country when it is empty.
+func (s LegalSuffix) usedIn(country string) bool {
+	if country == "" {
+		return true
+	}
+	for _, c := range s.Countries {
+		if strings.EqualFold(c, country) {
+			return true
+		}
+	}
+	return false
+}
+
+// clone return a copy of the suffix, so the callers of Parse cannot change the dictionary.
+func (s LegalSuffix) clone() *LegalSuffix {
+	s.Countries = append([]string(nil), s.Countries...)
+	s.Forms = append([]string(nil), s.Forms...)
+	return &s
+}
+
+// legalSuffixes holds the legal forms recognized at the end of an organization name.
+var legalSuffixes = []LegalSuffix{
+	{Canonical: "sa", Family: "sa", Countries: []string{"ES", "AR", "MX", "CL", "CO", "PE", "FR", "BE", "CH"}, Forms: []string{"sa", "s a", "sociedad anonima", "societe anonyme"}},
+	{Canonical: "sa de cv", Family: "sa", Countries: []string{"MX"}, Forms: []string{"sa de cv", "s a de c v", "sociedad anonima de capital variable"}},
+	{Canonical: "sab de cv", Family: "sa", Countries: []string{"MX"}, Forms: []string{"sab de cv", "s a b de c v"}},
+	{Canonical: "saa", Family: "sa", Countries: []string{"PE"}, Forms: []string{"saa", "sociedad anonima abierta"}},
+	{Canonical: "sac", Family: "sa", Countries: []string{"PE"}, Forms: []string{"sac", "sociedad anonima cerrada"}},
+	{Canonical: "sas", Family: "sas", Countries: []string{"CO", "FR"}, Forms: []string{"sas", "s a s", "sociedad por acciones simplificada", "societe par actions simplifiee"}},
+	{Canonical: "sl", Family: "limited", Countries: []string{"ES"}, Forms: []string{"sl", "s l", "sociedad limitada"}},
+	{Canonical: "slu", Family: "limited", Countries: []string{"ES"}, Forms: []string{"slu", "sociedad limitada unipersonal"}},
+	{Canonical: "srl", Family: "limited", Countries: []string{"AR", "IT", "PE", "RO"}, Forms: []string{"srl", "s r l", "sociedad de responsabilidad limitada", "societa a responsabilita limitata"}},
+	{Canonical: "s de rl", Family: "limited", Countries: []string{"MX"}, Forms: []string{"s de rl", "s de r l", "s de rl de cv", "s de r l de c v"}},
+	{Canonical: "ltda", Family: "limited", Countries: []string{"CO", "CL", "BR"}, Forms: []string{"ltda", "limitada"}},
+	{Canonical: "ltd", Family: "limited", Countries: []string{"GB", "IE", "IN", "AU", "NZ"}, Forms: []string{"ltd", "limited"}},
+	{Canonical: "llc", Family: "limited", Countries: []string{"US"}, Forms: []string{"llc", "l l c", "limited liability company"}},
+	{Canonical: "llp", Family: "partnership", Countries: []string{"US", "GB"}, Forms: []string{"llp", "l l p", "limited liability partnership"}},
+	{Canonical: "lp", Family: "partnership", Countries: []string{"US", "GB"}, Forms: []string{"lp", "limited partnership"}},
+	{Canonical: "plc", Family: "public", Countries: []string{"GB", "IE"}, Forms: []string{"plc", "public limited company"}},
+	{Canonical: "inc", Family: "corporation", Countries: []string{"US", "CA"}, Forms: []string{"inc", "incorporated"}},
+	{Canonical: "corp", Family: "corporation", Countries: []string{"US", "CA"}, Forms: []string{"corp", "corporation"}},
+	{Canonical: "co", Family: "company", Countries: []string{"US", "GB"}, Forms: []string{"co", "company", "and co", "and company"}},
+	{Canonical: "gmbh", Family: "limited", Countries: []string{"DE", "AT", "CH"}, Forms: []string{"gmbh", "gesellschaft mit beschrankter haftung"}},
+	{Canonical: "ag", Family: "sa", Countries: []string{"DE", "AT", "CH"}, Forms: []string{"ag", "aktiengesellschaft"}},
+	{Canonical: "bv", Family: "limited", Countries: []string{"NL", "BE"}, Forms: []string{"bv", "b v", "besloten vennootschap"}},
+	{Canonical: "nv", Family: "sa", Countries: []string{"NL", "BE"}, Forms: []string{"nv", "n v", "naamloze vennootschap"}},
+	{Canonical: "sarl", Family: "limited", Countries: []string{"FR", "LU", "MA"}, Forms: []string{"sarl", "societe a responsabilite limitee"}},
+	{Canonical: "spa", Family: "sa", Countries: []string{"IT", "CL"}, Forms: []string{"spa", "s p a", "societa per azioni", "sociedad por acciones"}},
+}
+
+// stopWords are dropped from the organization name before comparing it.
+var stopWords = map[string]bool{
+	"the": true,
+	"and": true,
+	"of":  true,
+	"y":   true,
+	"e":   true,
+	"de":  true,
+	"del": true,
+	"la":  true,
+	"las": true,
+	"el":  true,
+	"los": true,
+	"le":  true,
+	"les": true,
+	"des": true,
+	"der": true,
+	"die": true,
+	"und": true,
+}
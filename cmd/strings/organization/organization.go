@@ -0,0 +1,90 @@
+package organization
+
+import (
+	"strings"
+	"unicode"
+
+	"golibs/cmd/internal/textnorm"
+)
+
+// Organization holds the normalized name of an organization and its legal suffix, when any.
+type Organization struct {
+	Name   string
+	Suffix *LegalSuffix
+}
+
+// Parse normalizes an organization name, detects its legal suffix, and drops
+// punctuation and stop words. "ACME S.A. de C.V." is parsed as the name "acme"
+// with the suffix "sa de cv".
+func Parse(name string) Organization {
+	return ParseInCountry(name, "")
+}
+
+// ParseInCountry is Parse only detecting the legal suffixes used in country, an ISO 3166-1
+// alpha-2 code, so "Acme SA" keeps "sa" in its name for the United States. An empty country
+// detects the suffixes of every country.
+func ParseInCountry(name, country string) Organization {
+	var org Organization
+
+	tokens := tokenize(name)
+
+	found, length := -1, 0
+	for i := range legalSuffixes {
+		if !legalSuffixes[i].usedIn(country) {
+			continue
+		}
+		for _, form := range legalSuffixes[i].Forms {
+			words := strings.Fields(form)
+			if len(words) <= length || len(words) >= len(tokens) || !hasSuffix(tokens, words) {
+				continue
+			}
+			found, length = i, len(words)
+		}
+	}
+	if found >= 0 {
+		org.Suffix = legalSuffixes[found].clone()
+	}
+	tokens = tokens[:len(tokens)-length]
+
+	var words []string
+	for _, token := range tokens {
+		if !stopWords[token] {
+			words = append(words, token)
+		}
+	}
+	if len(words) == 0 {
+		words = tokens
+	}
+	org.Name = strings.Join(words, " ")
+
+	return org
+}
+
+func hasSuffix(tokens, suffix []string) bool {
+	offset := len(tokens) - len(suffix)
+	for i, word := range suffix {
+		if tokens[offset+i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize lowercase the name, removes the accents, joins the dotted abbreviations
+// and split it in words.
+func tokenize(name string) []string {
+	name = strings.ToLower(textnorm.RemoveAccents(name))
+	name = strings.ReplaceAll(name, "&", " and ")
+
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '.' || r == '\'' || r == '’':
+			return -1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return r
+		}
+		return ' '
+	}, name)
+
+	return strings.Fields(name)
+}
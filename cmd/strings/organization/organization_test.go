@@ -0,0 +1,139 @@
+package organization_test
+
+import (
+	"golibs/cmd/strings/organization"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		org        string
+		wantName   string
+		wantSuffix string
+	}{
+		{name: "Dotted", org: "ACME S.A.", wantName: "acme", wantSuffix: "sa"},
+		{name: "Spelled", org: "Acme Sociedad Anónima", wantName: "acme", wantSuffix: "sa"},
+		{name: "Mexican", org: "Grupo Bimbo, S.A.B. de C.V.", wantName: "grupo bimbo", wantSuffix: "sab de cv"},
+		{name: "Stop words", org: "The Procter & Gamble Co.", wantName: "procter gamble", wantSuffix: "co"},
+		{name: "Spaced", org: "Banco de la Nación S. A.", wantName: "banco nacion", wantSuffix: "sa"},
+		{name: "Only suffix", org: "Ltda", wantName: "ltda", wantSuffix: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := organization.Parse(tt.org)
+			if got.Name != tt.wantName {
+				t.Errorf("Parse() name = %v, want %v", got.Name, tt.wantName)
+			}
+			var suffix string
+			if got.Suffix != nil {
+				suffix = got.Suffix.Canonical
+			}
+			if suffix != tt.wantSuffix {
+				t.Errorf("Parse() suffix = %v, want %v", suffix, tt.wantSuffix)
+			}
+		})
+	}
+}
+
+func TestParse_SuffixIsACopy(t *testing.T) {
+	got := organization.Parse("ACME S.A.")
+	got.Suffix.Canonical = "changed"
+	got.Suffix.Forms[0] = "changed"
+
+	again := organization.Parse("ACME S.A.")
+	if again.Suffix.Canonical != "sa" || again.Suffix.Forms[0] != "sa" {
+		t.Errorf("Parse() suffix = %+v after changing a previous result", again.Suffix)
+	}
+}
+
+func TestParseInCountry(t *testing.T) {
+	tests := []struct {
+		org        string
+		country    string
+		wantName   string
+		wantSuffix string
+	}{
+		{org: "ACME S.A.", country: "es", wantName: "acme", wantSuffix: "sa"},
+		{org: "ACME S.A.", country: "US", wantName: "acme sa", wantSuffix: ""},
+		{org: "Acme Ltda", country: "CO", wantName: "acme", wantSuffix: "ltda"},
+		{org: "Acme Ltda", country: "GB", wantName: "acme ltda", wantSuffix: ""},
+		{org: "Acme SAS", country: "", wantName: "acme", wantSuffix: "sas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.org+" in "+tt.country, func(t *testing.T) {
+			got := organization.ParseInCountry(tt.org, tt.country)
+			suffix := ""
+			if got.Suffix != nil {
+				suffix = got.Suffix.Canonical
+			}
+			if got.Name != tt.wantName || suffix != tt.wantSuffix {
+				t.Errorf("ParseInCountry() = %q with suffix %q, want %q with %q", got.Name, suffix, tt.wantName, tt.wantSuffix)
+			}
+		})
+	}
+
+	if got := organization.GetSimilarityInCountry("Acme SA", "Acme Inc", "US"); got.SourceSuffix != nil || got.TargetSuffix == nil {
+		t.Errorf("GetSimilarityInCountry() suffixes = %+v, %+v, want only inc", got.SourceSuffix, got.TargetSuffix)
+	}
+}
+
+func TestGetSimilarity(t *testing.T) {
+	type args struct {
+		source string
+		target string
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "Spelled suffix",
+			args: args{
+				source: "ACME S.A.",
+				target: "Acme Sociedad Anónima",
+			},
+			want: 1,
+		},
+		{
+			name: "Same family",
+			args: args{
+				source: "ACME S.A.",
+				target: "ACME SA de CV",
+			},
+			want: 1,
+		},
+		{
+			name: "Different family",
+			args: args{
+				source: "Acme Inc",
+				target: "Acme GmbH",
+			},
+			want: 0.9,
+		},
+		{
+			name: "Reordered",
+			args: args{
+				source: "Bimbo Group",
+				target: "Grupo Bimbo SA",
+			},
+			want: 0.9466666666666667,
+		},
+		{
+			name: "Extra word",
+			args: args{
+				source: "Acme Holdings LLC",
+				target: "Acme LLC",
+			},
+			want: 0.75,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := organization.GetSimilarity(tt.args.source, tt.args.target); got.Score != tt.want {
+				t.Errorf("GetSimilarity() = %v, want %v", got.Score, tt.want)
+			}
+		})
+	}
+}
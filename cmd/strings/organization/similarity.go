@@ -0,0 +1,47 @@
+package organization
+
+import (
+	ustrings "golibs/cmd/strings"
+)
+
+// DefaultSuffixPenalty is the share of the score lost when both names have legal
+// suffixes of different families, such as "Inc." and "GmbH".
+const DefaultSuffixPenalty = 0.1
+
+type Match struct {
+	Score        float64
+	SourceSuffix *LegalSuffix
+	TargetSuffix *LegalSuffix
+}
+
+// GetSimilarity compares two organization names with DefaultSuffixPenalty.
+func GetSimilarity(source, target string) Match {
+	return GetOrganizationSimilarity(Parse(source), Parse(target), DefaultSuffixPenalty)
+}
+
+// GetSimilarityInCountry is GetSimilarity for two names of organizations in country, only
+// detecting its legal suffixes.
+func GetSimilarityInCountry(source, target, country string) Match {
+	return GetOrganizationSimilarity(ParseInCountry(source, country), ParseInCountry(target, country), DefaultSuffixPenalty)
+}
+
+// GetOrganizationSimilarity compares the names with Monge-Elkan over Jaro-Winkler, so word
+// order does not matter, and applies suffixPenalty when the legal suffixes disagree.
+func GetOrganizationSimilarity(source, target Organization, suffixPenalty float64) Match {
+	match := Match{
+		SourceSuffix: source.Suffix,
+		TargetSuffix: target.Suffix,
+	}
+
+	if source.Name == target.Name {
+		match.Score = 1
+	} else {
+		match.Score = ustrings.GetMongeElkanSimilarity(source.Name, target.Name, nil)
+	}
+
+	if source.Suffix != nil && target.Suffix != nil && source.Suffix.Family != target.Suffix.Family {
+		match.Score *= 1 - suffixPenalty
+	}
+
+	return match
+}
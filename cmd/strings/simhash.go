@@ -0,0 +1,212 @@
+package strings
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidDistance is returned by NewSimHashIndex when the distance is out of range.
+var ErrInvalidDistance = errors.New("hamming distance must be between 0 and 31")
+
+// simHashShingle is the size in runes of the shingles hashed by SimHash.
+const simHashShingle = 4
+
+// SimHash return the 64-bit SimHash fingerprint of text, computed over the shingles of
+// its normalized form. Similar texts have fingerprints at a small Hamming distance.
+func SimHash(text string) uint64 {
+	runes := []rune(strings.Join(tokenize(text), " "))
+
+	size := simHashShingle
+	if len(runes) < size {
+		size = len(runes)
+	}
+
+	features := make(map[string]float64)
+	for i := 0; size > 0 && i+size <= len(runes); i++ {
+		features[string(runes[i:i+size])]++
+	}
+
+	return SimHashWeighted(features)
+}
+
+// SimHashWeighted return the SimHash fingerprint of a set of features and their weights.
+func SimHashWeighted(features map[string]float64) uint64 {
+	var vector [64]float64
+	for feature, weight := range features {
+		hasher := fnv.New64a()
+		_, _ = hasher.Write([]byte(feature))
+		h := hasher.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if h&(1<<uint(bit)) != 0 {
+				vector[bit] += weight
+			} else {
+				vector[bit] -= weight
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for bit, v := range vector {
+		if v > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+	return fingerprint
+}
+
+// HammingDistance return the number of different bits of two fingerprints.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// SimHashMatch is a result of SimHashIndex.Query.
+type SimHashMatch struct {
+	ID          string
+	Fingerprint uint64
+	Distance    int
+}
+
+// SimHashIndex finds the fingerprints within a Hamming distance k of a query. The 64 bits
+// are split in k+1 blocks and, as two fingerprints within distance k share at least one of
+// them, a table sorted by each block permuted to the top bits is kept, like Manku et al.
+//
+// A SimHashIndex is not safe for concurrent use.
+type SimHashIndex struct {
+	k      int
+	tables []simHashTable
+	ids    map[string]uint64
+}
+
+type simHashTable struct {
+	// rotation moves the block to the top bits, width is its size.
+	rotation int
+	width    int
+	entries  []simHashEntry
+	sorted   bool
+}
+
+type simHashEntry struct {
+	key         uint64
+	id          string
+	fingerprint uint64
+}
+
+// NewSimHashIndex returns an empty index for queries within Hamming distance k.
+func NewSimHashIndex(k int) (*SimHashIndex, error) {
+	if k < 0 || k > 31 {
+		return nil, ErrInvalidDistance
+	}
+
+	idx := &SimHashIndex{
+		k:      k,
+		tables: make([]simHashTable, k+1),
+		ids:    make(map[string]uint64),
+	}
+
+	start := 0
+	for i := range idx.tables {
+		width := 64 / (k + 1)
+		if i < 64%(k+1) {
+			width++
+		}
+		end := start + width
+		idx.tables[i] = simHashTable{rotation: 64 - end, width: width, sorted: true}
+		start = end
+	}
+
+	return idx, nil
+}
+
+// Len return the number of fingerprints in the index.
+func (idx *SimHashIndex) Len() int {
+	return len(idx.ids)
+}
+
+// Insert adds a fingerprint under id, replacing the previous one of the same id.
+func (idx *SimHashIndex) Insert(id string, fingerprint uint64) {
+	if _, ok := idx.ids[id]; ok {
+		idx.Remove(id)
+	}
+
+	idx.ids[id] = fingerprint
+	for i := range idx.tables {
+		table := &idx.tables[i]
+		table.entries = append(table.entries, simHashEntry{
+			key:         bits.RotateLeft64(fingerprint, table.rotation),
+			id:          id,
+			fingerprint: fingerprint,
+		})
+		table.sorted = false
+	}
+}
+
+// Remove deletes id from the index, reporting whether it was there.
+func (idx *SimHashIndex) Remove(id string) bool {
+	if _, ok := idx.ids[id]; !ok {
+		return false
+	}
+
+	delete(idx.ids, id)
+	for i := range idx.tables {
+		table := &idx.tables[i]
+		entries := table.entries[:0]
+		for _, entry := range table.entries {
+			if entry.id != id {
+				entries = append(entries, entry)
+			}
+		}
+		table.entries = entries
+	}
+
+	return true
+}
+
+// Query return the fingerprints within the index distance of fingerprint, the closest first.
+func (idx *SimHashIndex) Query(fingerprint uint64) []SimHashMatch {
+	seen := make(map[string]bool)
+	var matches []SimHashMatch
+
+	for i := range idx.tables {
+		table := &idx.tables[i]
+		table.sort()
+
+		shift := uint(64 - table.width)
+		prefix := bits.RotateLeft64(fingerprint, table.rotation) >> shift
+
+		first := sort.Search(len(table.entries), func(j int) bool {
+			return table.entries[j].key>>shift >= prefix
+		})
+		for j := first; j < len(table.entries) && table.entries[j].key>>shift == prefix; j++ {
+			entry := table.entries[j]
+			if seen[entry.id] {
+				continue
+			}
+			seen[entry.id] = true
+			if d := HammingDistance(fingerprint, entry.fingerprint); d <= idx.k {
+				matches = append(matches, SimHashMatch{ID: entry.id, Fingerprint: entry.fingerprint, Distance: d})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	return matches
+}
+
+func (t *simHashTable) sort() {
+	if t.sorted {
+		return
+	}
+	sort.Slice(t.entries, func(i, j int) bool {
+		return t.entries[i].key < t.entries[j].key
+	})
+	t.sorted = true
+}
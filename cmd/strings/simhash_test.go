@@ -0,0 +1,113 @@
+package strings_test
+
+import (
+	"errors"
+	"golibs/cmd/strings"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSimHash(t *testing.T) {
+	a := strings.SimHash(descriptions["a"])
+
+	if got := strings.HammingDistance(a, strings.SimHash("LAPTOP lenovo thinkpad t14 gen 2, 16gb ram, 512gb ssd, intel core i7")); got != 0 {
+		t.Errorf("HammingDistance() same text = %v, want 0", got)
+	}
+
+	near := strings.HammingDistance(a, strings.SimHash(descriptions["c"]))
+	far := strings.HammingDistance(a, strings.SimHash(descriptions["d"]))
+	if near >= far || near > 10 {
+		t.Errorf("HammingDistance() near = %v, far = %v", near, far)
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b uint64
+		want int
+	}{
+		{name: "Equals", a: 0xff00, b: 0xff00, want: 0},
+		{name: "One bit", a: 0, b: 1 << 63, want: 1},
+		{name: "All bits", a: 0, b: ^uint64(0), want: 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.HammingDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("HammingDistance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimHashIndex_Query(t *testing.T) {
+	const k = 3
+	random := rand.New(rand.NewSource(1))
+
+	idx, err := strings.NewSimHashIndex(k)
+	if err != nil {
+		t.Fatalf("NewSimHashIndex() error = %v", err)
+	}
+
+	query := random.Uint64()
+	fingerprints := map[string]uint64{}
+	for i := 0; i < 500; i++ {
+		fingerprint := random.Uint64()
+		// flip up to six random bits of the query so some fingerprints are close
+		if i%5 == 0 {
+			fingerprint = query
+			for j := 0; j < i%7; j++ {
+				fingerprint ^= 1 << uint(random.Intn(64))
+			}
+		}
+		id := string(rune('a'+i%26)) + string(rune('0'+i/26))
+		fingerprints[id] = fingerprint
+		idx.Insert(id, fingerprint)
+	}
+
+	var want []string
+	for id, fingerprint := range fingerprints {
+		if strings.HammingDistance(query, fingerprint) <= k {
+			want = append(want, id)
+		}
+	}
+	sort.Strings(want)
+
+	var got []string
+	for _, match := range idx.Query(query) {
+		got = append(got, match.ID)
+	}
+	sort.Strings(got)
+
+	if len(want) == 0 || !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() = %v, want %v", got, want)
+	}
+}
+
+func TestSimHashIndex_InsertRemove(t *testing.T) {
+	idx, _ := strings.NewSimHashIndex(2)
+
+	idx.Insert("a", 0b1111)
+	idx.Insert("b", 0b0100)
+	idx.Insert("a", 0b0111)
+
+	want := []strings.SimHashMatch{
+		{ID: "a", Fingerprint: 0b0111, Distance: 0},
+		{ID: "b", Fingerprint: 0b0100, Distance: 2},
+	}
+	if got := idx.Query(0b0111); !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() = %v, want %v", got, want)
+	}
+
+	if !idx.Remove("a") || idx.Remove("a") || idx.Len() != 1 {
+		t.Errorf("Remove() should only succeed once")
+	}
+}
+
+func TestNewSimHashIndex(t *testing.T) {
+	if _, err := strings.NewSimHashIndex(32); !errors.Is(err, strings.ErrInvalidDistance) {
+		t.Errorf("NewSimHashIndex() error = %v, want %v", err, strings.ErrInvalidDistance)
+	}
+}
@@ -63,9 +63,10 @@ func GetJaroWinklerSimilarity(source, target string) float64 {
 func normalized(source, target string, options Options) float64 {
 	d := levenshteinDistance([]rune(source), []rune(target), options)
 
-	var m = len(source)
-	var n = len(target)
+	return normalizedDistance(d, len(source), len(target))
+}
 
+func normalizedDistance(d float64, m, n int) float64 {
 	if m > n {
 		return d / float64(m)
 	}
@@ -78,48 +79,49 @@ func levenshteinDistance(source, target []rune, options Options) float64 {
 		return 0
 	}
 
+	if options.unitCosts() {
+		return float64(new(bitParallel).distance(source, target))
+	}
+
+	return levenshteinRows(source, target, options, make([]float64, len(target)+1), make([]float64, len(target)+1))
+}
+
+// levenshteinRows computes the distance keeping only two rows of the matrix,
+// previous and current must have room for len(target)+1 values.
+func levenshteinRows(source, target []rune, options Options, previous, current []float64) float64 {
 	var rows = len(source) + 1
 	var columns = len(target) + 1
 
-	distance := make([][]float64, rows)
-
-	for i := range distance {
-		distance[i] = make([]float64, columns)
-	}
-
-	for i := 0; i < rows; i++ {
-		distance[i][0] = float64(i) * options.DelCost
-	}
+	previous = previous[:columns]
+	current = current[:columns]
 
 	for j := 0; j < columns; j++ {
-		distance[0][j] = float64(j) * options.InsCost
+		previous[j] = float64(j) * options.InsCost
 	}
 
 	for i := 1; i < rows; i++ {
+		current[0] = float64(i) * options.DelCost
+
 		for j := 1; j < columns; j++ {
-			deletion := distance[i-1][j] + options.DelCost
-			insertion := distance[i][j-1] + options.InsCost
-			substitutionOrEqual := distance[i-1][j-1]
+			deletion := previous[j] + options.DelCost
+			insertion := current[j-1] + options.InsCost
+			substitutionOrEqual := previous[j-1]
 
 			if source[i-1] != target[j-1] {
 				substitutionOrEqual += options.SubCost
 			}
 
-			distance[i][j] = math.Min(deletion, math.Min(insertion, substitutionOrEqual))
+			current[j] = math.Min(deletion, math.Min(insertion, substitutionOrEqual))
 		}
+
+		previous, current = current, previous
 	}
 
-	return distance[rows-1][columns-1]
+	return previous[columns-1]
 }
 
 func jaroWinklerDistance(s1, s2 string) float64 {
 
-	s1Matches := make([]bool, len(s1)) // |s1|
-	s2Matches := make([]bool, len(s2)) // |s2|
-
-	var matchingCharacters = 0.0
-	var transpositions = 0.0
-
 	// sanity checks
 
 	// return 0 if either one is empty string
@@ -127,15 +129,23 @@ func jaroWinklerDistance(s1, s2 string) float64 {
 		return 0 // no similarity
 	}
 
-	// return 1 if both strings are empty
-	if len(s1) == 0 && len(s2) == 0 {
-		return 1 // exact match
-	}
-
 	if strings.EqualFold(s1, s2) { // case insensitive
 		return 1 // exact match
 	}
 
+	s1Matches := make([]bool, len(s1)) // |s1|
+	s2Matches := make([]bool, len(s2)) // |s2|
+
+	return jaroWinkler([]byte(s1), []byte(s2), s1Matches, s2Matches)
+}
+
+// jaroWinkler computes the similarity of two non empty and different strings,
+// s1Matches and s2Matches must be zeroed and as long as s1 and s2.
+func jaroWinkler(s1, s2 []byte, s1Matches, s2Matches []bool) float64 {
+
+	var matchingCharacters = 0.0
+	var transpositions = 0.0
+
 	// Two characters from s1 and s2 respectively,
 	// are considered matching only if they are the same and not farther than
 	// [ max(|s1|,|s2|) / 2 ] - 1
@@ -201,7 +211,7 @@ func jaroWinklerDistance(s1, s2 string) float64 {
 	p := 0.1
 
 	if weight > 0.7 {
-		for (l < 4) && s1[l] == s2[l] {
+		for (l < 4) && l < len(s1) && l < len(s2) && s1[l] == s2[l] {
 			l++
 		}
 
@@ -0,0 +1,316 @@
+package strings
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Errors returned by CompareStructs.
+var (
+	ErrNotStruct      = errors.New("source and target must be structs or pointers to structs")
+	ErrDifferentTypes = errors.New("source and target must be of the same type")
+	ErrInvalidTag     = errors.New("invalid fuzzy tag")
+	ErrUnknownMetric  = errors.New("unknown fuzzy metric")
+)
+
+// TagName is the struct tag read by CompareStructs, for example
+//
+//	Name string `fuzzy:"metric=jw,weight=0.4,normalize=name"`
+//
+// Supported keys are metric (default jw), weight (default 1), normalize (default none)
+// and omitempty, which skips the field when it is empty in either struct.
+const TagName = "fuzzy"
+
+type (
+	RecordMatch struct {
+		Similarity float64
+		Fields     []FieldMatch
+	}
+	FieldMatch struct {
+		Field      string
+		Metric     string
+		Weight     float64
+		Similarity float64
+		Skipped    bool
+	}
+)
+
+type fieldTag struct {
+	index     int
+	name      string
+	metric    string
+	weight    float64
+	normalize string
+	omitempty bool
+}
+
+var (
+	registryMu  sync.RWMutex
+	metrics     = map[string]SimilarityFunc{}
+	normalizers = map[string]func(string) string{}
+	tagCache    sync.Map // reflect.Type -> []fieldTag
+)
+
+func init() {
+	levenshtein := func(source, target string) float64 {
+		return GetLevenshteinSimilarity(source, target, DefaultOptions)
+	}
+	media := func(source, target string) float64 {
+		return GetSimilarity(source, target).Percentage.Media
+	}
+	mongeElkan := func(source, target string) float64 {
+		return GetMongeElkanSimilarity(source, target, nil)
+	}
+	exact := func(source, target string) float64 {
+		if source == target {
+			return 1
+		}
+		return 0
+	}
+
+	for name, metric := range map[string]SimilarityFunc{
+		"jw":          GetJaroWinklerSimilarity,
+		"jarowinkler": GetJaroWinklerSimilarity,
+		"lev":         levenshtein,
+		"levenshtein": levenshtein,
+		"media":       media,
+		"me":          mongeElkan,
+		"mongeelkan":  mongeElkan,
+		"exact":       exact,
+		"number":      GetNumericSimilarity,
+		"date":        GetDateSimilarity,
+		"phone":       GetPhoneSimilarity,
+		"id":          GetIDSimilarity,
+		"typed":       GetTypedSimilarity,
+	} {
+		RegisterMetric(name, metric)
+	}
+
+	for name, normalizer := range map[string]func(string) string{
+		"none":   func(str string) string { return str },
+		"name":   normalizeName,
+		"digits": normalizeDigits,
+		"sorted": normalizeSorted,
+	} {
+		RegisterNormalizer(name, normalizer)
+	}
+}
+
+// RegisterMetric makes a similarity available to the fuzzy tag under name.
+func RegisterMetric(name string, metric SimilarityFunc) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	metrics[name] = metric
+}
+
+// RegisterNormalizer makes a normalization available to the fuzzy tag under name.
+func RegisterNormalizer(name string, normalizer func(string) string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	normalizers[name] = normalizer
+}
+
+// CompareStructs compares the fields of two structs of the same type tagged with fuzzy
+// and returns their weighted similarity and the similarity of every field.
+// Untagged fields and fields tagged with "-" are ignored.
+func CompareStructs(source, target any) (RecordMatch, error) {
+	var match RecordMatch
+
+	srcV := indirect(reflect.ValueOf(source))
+	tgtV := indirect(reflect.ValueOf(target))
+
+	if srcV.Kind() != reflect.Struct || tgtV.Kind() != reflect.Struct {
+		return match, ErrNotStruct
+	}
+
+	if srcV.Type() != tgtV.Type() {
+		return match, ErrDifferentTypes
+	}
+
+	tags, err := structTags(srcV.Type())
+	if err != nil {
+		return match, err
+	}
+
+	metricFuncs, normalizeFuncs, err := resolveTags(tags)
+	if err != nil {
+		return match, err
+	}
+
+	var score, total float64
+	for i, tag := range tags {
+		metric, normalize := metricFuncs[i], normalizeFuncs[i]
+		s := normalize(fieldString(srcV.Field(tag.index)))
+		t := normalize(fieldString(tgtV.Field(tag.index)))
+
+		field := FieldMatch{
+			Field:  tag.name,
+			Metric: tag.metric,
+			Weight: tag.weight,
+		}
+
+		if tag.omitempty && (s == "" || t == "") {
+			field.Skipped = true
+		} else {
+			field.Similarity = metric(s, t)
+			score += field.Similarity * tag.weight
+			total += tag.weight
+		}
+
+		match.Fields = append(match.Fields, field)
+	}
+
+	if total > 0 {
+		match.Similarity = score / total
+	}
+
+	return match, nil
+}
+
+// resolveTags return the metric and normalizer of every tag, looked up under the registry lock
+// so they can run without it, even when they register others.
+func resolveTags(tags []fieldTag) ([]SimilarityFunc, []func(string) string, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	metricFuncs := make([]SimilarityFunc, len(tags))
+	normalizeFuncs := make([]func(string) string, len(tags))
+	for i, tag := range tags {
+		metric, ok := metrics[tag.metric]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s in field %s", ErrUnknownMetric, tag.metric, tag.name)
+		}
+		normalize, ok := normalizers[tag.normalize]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: unknown normalize %s in field %s", ErrInvalidTag, tag.normalize, tag.name)
+		}
+		metricFuncs[i], normalizeFuncs[i] = metric, normalize
+	}
+	return metricFuncs, normalizeFuncs, nil
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func fieldString(v reflect.Value) string {
+	v = indirect(v)
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	if stringer, ok := v.Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// structTags parses and caches the fuzzy tags of a struct type.
+func structTags(t reflect.Type) ([]fieldTag, error) {
+	if cached, ok := tagCache.Load(t); ok {
+		return cached.([]fieldTag), nil
+	}
+
+	var tags []fieldTag
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value, ok := field.Tag.Lookup(TagName)
+		if !ok || value == "-" || !field.IsExported() {
+			continue
+		}
+
+		tag, err := parseTag(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w in field %s: %v", ErrInvalidTag, field.Name, err)
+		}
+		tag.index = i
+		tag.name = field.Name
+		tags = append(tags, tag)
+	}
+
+	tagCache.Store(t, tags)
+	return tags, nil
+}
+
+func parseTag(value string) (fieldTag, error) {
+	tag := fieldTag{
+		metric:    "jw",
+		weight:    1,
+		normalize: "none",
+	}
+
+	for _, option := range strings.Split(value, ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		if option == "omitempty" {
+			tag.omitempty = true
+			continue
+		}
+
+		key, val, found := strings.Cut(option, "=")
+		if !found {
+			return tag, fmt.Errorf("option %q is not key=value", option)
+		}
+
+		switch key {
+		case "metric":
+			tag.metric = val
+		case "normalize":
+			tag.normalize = val
+		case "weight":
+			weight, err := strconv.ParseFloat(val, 64)
+			if err != nil || weight < 0 {
+				return tag, fmt.Errorf("invalid weight %q", val)
+			}
+			tag.weight = weight
+		default:
+			return tag, fmt.Errorf("unknown option %q", key)
+		}
+	}
+
+	return tag, nil
+}
+
+// normalizeName drops punctuation and collapse the spaces between words.
+func normalizeName(str string) string {
+	str = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) {
+			return ' '
+		}
+		return r
+	}, str)
+	return strings.Join(strings.Fields(str), " ")
+}
+
+// normalizeDigits keeps only the digits, useful for phones and documents.
+func normalizeDigits(str string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, str)
+}
+
+// normalizeSorted sorts the words, so their order does not matter.
+func normalizeSorted(str string) string {
+	words := tokenize(normalizeName(str))
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
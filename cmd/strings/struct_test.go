@@ -0,0 +1,153 @@
+package strings_test
+
+import (
+	"errors"
+	"golibs/cmd/strings"
+	"testing"
+	"time"
+)
+
+type customer struct {
+	Name    string `fuzzy:"metric=jw,weight=0.4,normalize=name"`
+	Surname string `fuzzy:"metric=me,weight=0.4,normalize=sorted"`
+	Phone   string `fuzzy:"metric=exact,weight=0.2,normalize=digits,omitempty"`
+	Age     int
+}
+
+func TestCompareStructs(t *testing.T) {
+	type args struct {
+		source any
+		target any
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr error
+	}{
+		{
+			name: "Equals",
+			args: args{
+				source: customer{Name: "Reynier", Surname: "Gonzalez Cruz", Phone: "+53 555-1234"},
+				target: customer{Name: "Reynier", Surname: "Gonzalez Cruz", Phone: "+53 555-1234", Age: 40},
+			},
+			want: 1,
+		},
+		{
+			name: "Pointer and normalizations",
+			args: args{
+				source: customer{Name: "Reynier", Surname: "Gonzalez Cruz", Phone: "+53 555-1234"},
+				target: &customer{Name: "Reinier", Surname: "Cruz González", Phone: "53 5551234"},
+			},
+			want: 0.9517460317460318,
+		},
+		{
+			name: "Empty field omitted",
+			args: args{
+				source: customer{Name: "Reynier", Surname: "Gonzalez Cruz"},
+				target: customer{Name: "Reinier", Surname: "Cruz", Phone: "53 5551234"},
+			},
+			want: 0.8719742063492064,
+		},
+		{
+			name: "Not struct",
+			args: args{
+				source: "Reynier",
+				target: "Reinier",
+			},
+			wantErr: strings.ErrNotStruct,
+		},
+		{
+			name: "Different types",
+			args: args{
+				source: customer{},
+				target: struct{ Name string }{},
+			},
+			wantErr: strings.ErrDifferentTypes,
+		},
+		{
+			name: "Invalid tag",
+			args: args{
+				source: struct {
+					Name string `fuzzy:"weight=heavy"`
+				}{},
+				target: struct {
+					Name string `fuzzy:"weight=heavy"`
+				}{},
+			},
+			wantErr: strings.ErrInvalidTag,
+		},
+		{
+			name: "Unknown metric",
+			args: args{
+				source: struct {
+					Name string `fuzzy:"metric=soundex"`
+				}{},
+				target: struct {
+					Name string `fuzzy:"metric=soundex"`
+				}{},
+			},
+			wantErr: strings.ErrUnknownMetric,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := strings.CompareStructs(tt.args.source, tt.args.target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CompareStructs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.Similarity != tt.want {
+				t.Errorf("CompareStructs() = %v, want %v", got.Similarity, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareStructs_Fields(t *testing.T) {
+	got, err := strings.CompareStructs(
+		customer{Name: "Reynier", Surname: "Gonzalez Cruz"},
+		customer{Name: "Reinier", Surname: "Cruz", Phone: "53 5551234"},
+	)
+	if err != nil {
+		t.Fatalf("CompareStructs() error = %v", err)
+	}
+
+	want := []strings.FieldMatch{
+		{Field: "Name", Metric: "jw", Weight: 0.4, Similarity: 0.8793650793650793},
+		{Field: "Surname", Metric: "me", Weight: 0.4, Similarity: 0.8645833333333333},
+		{Field: "Phone", Metric: "exact", Weight: 0.2, Skipped: true},
+	}
+	if len(got.Fields) != len(want) {
+		t.Fatalf("CompareStructs() fields = %+v, want %+v", got.Fields, want)
+	}
+	for i := range want {
+		if got.Fields[i] != want[i] {
+			t.Errorf("CompareStructs() field = %+v, want %+v", got.Fields[i], want[i])
+		}
+	}
+}
+
+func TestCompareStructs_RegisterInMetric(t *testing.T) {
+	strings.RegisterMetric("registering", func(source, target string) float64 {
+		strings.RegisterNormalizer("registered", func(str string) string { return str })
+		return 1
+	})
+	type record struct {
+		Name string `fuzzy:"metric=registering"`
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := strings.CompareStructs(record{Name: "a"}, record{Name: "b"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("CompareStructs() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("CompareStructs() deadlocked with a metric registering a normalizer")
+	}
+}
@@ -0,0 +1,162 @@
+package strings
+
+import (
+	"math"
+	"strings"
+
+	"golibs/cmd/internal/textnorm"
+)
+
+// SimilarityFunc compares two strings and returns a similarity between 0 and 1.
+type SimilarityFunc func(source, target string) float64
+
+// DefaultSoftTFIDFThreshold is the minimum inner similarity for two tokens to be
+// considered a soft match in GetSoftTFIDFSimilarity.
+const DefaultSoftTFIDFThreshold = 0.9
+
+// IDFTable holds the document frequency of every token seen in a corpus.
+type IDFTable struct {
+	documents int
+	frequency map[string]int
+}
+
+// NewIDFTable builds an IDFTable from the given corpus, each entry being a document.
+func NewIDFTable(corpus []string) *IDFTable {
+	table := &IDFTable{frequency: make(map[string]int)}
+	for _, document := range corpus {
+		table.Add(document)
+	}
+	return table
+}
+
+// Add count a new document in the table.
+func (t *IDFTable) Add(document string) {
+	seen := make(map[string]bool)
+	for _, token := range tokenize(document) {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		t.frequency[token]++
+	}
+	t.documents++
+}
+
+// Documents return the number of documents added to the table.
+func (t *IDFTable) Documents() int {
+	return t.documents
+}
+
+// IDF return the smoothed inverse document frequency of a token. Tokens never seen
+// in the corpus get the highest weight.
+func (t *IDFTable) IDF(token string) float64 {
+	var df int
+	if tokens := tokenize(token); len(tokens) > 0 {
+		df = t.frequency[tokens[0]]
+	}
+	return math.Log(float64(t.documents+1)/float64(df+1)) + 1
+}
+
+// GetMongeElkanSimilarity compares every token of each string with its best
+// counterpart in the other one and returns the mean of both directions.
+// When inner is nil GetJaroWinklerSimilarity is used.
+func GetMongeElkanSimilarity(source, target string, inner SimilarityFunc) float64 {
+	return (GetMongeElkanAsymmetricSimilarity(source, target, inner) +
+		GetMongeElkanAsymmetricSimilarity(target, source, inner)) / 2
+}
+
+// GetMongeElkanAsymmetricSimilarity match every token of source with its best
+// counterpart in target and returns the mean of the best scores.
+// When inner is nil GetJaroWinklerSimilarity is used.
+func GetMongeElkanAsymmetricSimilarity(source, target string, inner SimilarityFunc) float64 {
+	if inner == nil {
+		inner = GetJaroWinklerSimilarity
+	}
+
+	sourceTokens := tokenize(source)
+	targetTokens := tokenize(target)
+
+	if len(sourceTokens) == 0 || len(targetTokens) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, s := range sourceTokens {
+		var best float64
+		for _, t := range targetTokens {
+			if sim := inner(s, t); sim > best {
+				best = sim
+			}
+		}
+		sum += best
+	}
+
+	return sum / float64(len(sourceTokens))
+}
+
+// GetSoftTFIDFSimilarity weights every token by its TF-IDF and adds the product of
+// the weights of the tokens whose inner similarity is at least threshold.
+// When inner is nil GetJaroWinklerSimilarity is used, and a nil idf weights every token the same.
+func GetSoftTFIDFSimilarity(source, target string, idf *IDFTable, inner SimilarityFunc, threshold float64) float64 {
+	if inner == nil {
+		inner = GetJaroWinklerSimilarity
+	}
+
+	sourceTokens, sourceWeights := tfidf(tokenize(source), idf)
+	targetTokens, targetWeights := tfidf(tokenize(target), idf)
+
+	if len(sourceTokens) == 0 || len(targetTokens) == 0 {
+		return 0
+	}
+
+	var sim float64
+	for _, s := range sourceTokens {
+		var best, bestWeight float64
+		for _, t := range targetTokens {
+			score := inner(s, t)
+			if score > best || (score == best && targetWeights[t] > bestWeight) {
+				best = score
+				bestWeight = targetWeights[t]
+			}
+		}
+		if best >= threshold {
+			sim += sourceWeights[s] * bestWeight * best
+		}
+	}
+
+	return math.Min(sim, 1)
+}
+
+// tfidf return the distinct tokens, in order of appearance, and their unit length TF-IDF vector.
+func tfidf(tokens []string, idf *IDFTable) ([]string, map[string]float64) {
+	var unique []string
+	weights := make(map[string]float64, len(tokens))
+	for _, token := range tokens {
+		if _, ok := weights[token]; !ok {
+			unique = append(unique, token)
+		}
+		weights[token]++
+	}
+
+	var length float64
+	for _, token := range unique {
+		w := math.Log(weights[token] + 1)
+		if idf != nil {
+			w *= idf.IDF(token)
+		}
+		weights[token] = w
+		length += w * w
+	}
+
+	length = math.Sqrt(length)
+	for _, token := range unique {
+		weights[token] /= length
+	}
+
+	return unique, weights
+}
+
+// tokenize normalizes str like strNormalization but keeps the words apart.
+func tokenize(str string) []string {
+	return strings.Fields(strings.ToLower(textnorm.RemoveAccents(str)))
+}
@@ -0,0 +1,191 @@
+package strings_test
+
+import (
+	"golibs/cmd/strings"
+	"testing"
+)
+
+func TestGetMongeElkanSimilarity(t *testing.T) {
+	type args struct {
+		source string
+		target string
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "Equals",
+			args: args{
+				source: "Reynier Gonzalez Cruz",
+				target: "Reynier Gonzalez Cruz",
+			},
+			want: 1,
+		},
+		{
+			name: "Reordered",
+			args: args{
+				source: "Reynier Gonzalez Cruz",
+				target: "González Cruz Reynier",
+			},
+			want: 1,
+		},
+		{
+			name: "Missing token",
+			args: args{
+				source: "Arelys RIVERO CASTRO",
+				target: "Arelys RIVERO",
+			},
+			want: 0.9259259259259258,
+		},
+		{
+			name: "Transposition",
+			args: args{
+				source: "MARTHA Mesa",
+				target: "MARHTA Mesa",
+			},
+			want: 0.9805555555555556,
+		},
+		{
+			name: "Empty",
+			args: args{
+				source: "",
+				target: "MARHTA Mesa",
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.GetMongeElkanSimilarity(tt.args.source, tt.args.target, nil); got != tt.want {
+				t.Errorf("GetMongeElkanSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMongeElkanAsymmetricSimilarity(t *testing.T) {
+	type args struct {
+		source string
+		target string
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "Contained",
+			args: args{
+				source: "Arelys RIVERO",
+				target: "Arelys RIVERO CASTRO",
+			},
+			want: 1,
+		},
+		{
+			name: "Containing",
+			args: args{
+				source: "Arelys RIVERO CASTRO",
+				target: "Arelys RIVERO",
+			},
+			want: 0.8518518518518517,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.GetMongeElkanAsymmetricSimilarity(tt.args.source, tt.args.target, nil); got != tt.want {
+				t.Errorf("GetMongeElkanAsymmetricSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSoftTFIDFSimilarity(t *testing.T) {
+	idf := strings.NewIDFTable([]string{
+		"Maria Gonzalez",
+		"Maria Perez",
+		"Jose Maria Perez",
+		"Jose Gonzalez",
+		"Reynier Gonzalez Cruz",
+	})
+
+	type args struct {
+		source string
+		target string
+		idf    *strings.IDFTable
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "Reordered",
+			args: args{
+				source: "Reynier Gonzalez Cruz",
+				target: "González Cruz Reynier",
+				idf:    idf,
+			},
+			want: 1,
+		},
+		{
+			name: "Common name shared",
+			args: args{
+				source: "Maria Gonzalez",
+				target: "Maria Perez",
+				idf:    idf,
+			},
+			want: 0.45163658061213224,
+		},
+		{
+			name: "Rare name shared",
+			args: args{
+				source: "Maria Reynier",
+				target: "Jose Reynier",
+				idf:    idf,
+			},
+			want: 0.6466602973341434,
+		},
+		{
+			name: "Soft match",
+			args: args{
+				source: "Reynier Gonzales",
+				target: "Reynier Gonzalez",
+				idf:    idf,
+			},
+			want: 0.9218118053758789,
+		},
+		{
+			name: "Without corpus",
+			args: args{
+				source: "Maria Reynier",
+				target: "Maria Perez",
+				idf:    nil,
+			},
+			want: 0.5000000000000001,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.GetSoftTFIDFSimilarity(tt.args.source, tt.args.target, tt.args.idf, nil, strings.DefaultSoftTFIDFThreshold)
+			if got != tt.want {
+				t.Errorf("GetSoftTFIDFSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDFTable_IDF(t *testing.T) {
+	idf := strings.NewIDFTable([]string{"Maria Gonzalez", "Maria Perez", "José Reynier"})
+
+	if idf.Documents() != 3 {
+		t.Errorf("Documents() = %v, want %v", idf.Documents(), 3)
+	}
+	if maria, jose := idf.IDF("MARIA"), idf.IDF("Jose"); maria >= jose {
+		t.Errorf("IDF() maria = %v should be lower than jose = %v", maria, jose)
+	}
+	if jose, unknown := idf.IDF("Jose"), idf.IDF("Arelys"); jose >= unknown {
+		t.Errorf("IDF() jose = %v should be lower than unknown = %v", jose, unknown)
+	}
+}
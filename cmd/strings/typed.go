@@ -0,0 +1,351 @@
+package strings
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golibs/cmd/utime"
+)
+
+// Kind is the type of value recognized in a string by DetectKind.
+type Kind int
+
+const (
+	KindText Kind = iota
+	KindNumber
+	KindDate
+	KindPhone
+	KindID
+)
+
+// String return the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindNumber:
+		return "number"
+	case KindDate:
+		return "date"
+	case KindPhone:
+		return "phone"
+	case KindID:
+		return "id"
+	}
+	return "text"
+}
+
+// dateOptions are the utime.ParseAny options to recognize dates, day first as in the rest of
+// the package and also with two digit years.
+var dateOptions = []utime.Option{utime.WithDayFirst(true), utime.WithExtraLayouts("2/1/06")}
+
+// dateSimilarityDays is the difference in days from which two dates have no similarity.
+const dateSimilarityDays = 365
+
+// DetectKind return the kind of value in str. Dates are detected first, then phone numbers,
+// which need a leading + or grouping of their 7 to 15 digits with spaces, dashes or
+// parentheses, then IDs, a single code mixing letters and digits, and finally numbers, with
+// at most a short unit or currency around them.
+func DetectKind(str string) Kind {
+	str = strings.TrimSpace(str)
+
+	if _, ok := parseDate(str); ok {
+		return KindDate
+	}
+	if isPhone(str) {
+		return KindPhone
+	}
+	if isID(str) {
+		return KindID
+	}
+	if _, rest, ok := extractNumber(str); ok {
+		letters := 0
+		digits := false
+		for _, r := range rest {
+			letters += btoi(unicode.IsLetter(r))
+			digits = digits || unicode.IsDigit(r)
+		}
+		if !digits && letters <= 3 {
+			return KindNumber
+		}
+	}
+	return KindText
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// GetTypedSimilarity compares source and target with the comparator of the kind detected in
+// both, a phone number being comparable to a plain number. Values of different kinds, or text,
+// are compared with the media of GetSimilarity.
+func GetTypedSimilarity(source, target string) float64 {
+	sourceKind, targetKind := DetectKind(source), DetectKind(target)
+
+	switch {
+	case sourceKind == KindPhone && (targetKind == KindPhone || targetKind == KindNumber),
+		targetKind == KindPhone && sourceKind == KindNumber:
+		return GetPhoneSimilarity(source, target)
+	case sourceKind != targetKind:
+		return GetSimilarity(source, target).Percentage.Media
+	case sourceKind == KindNumber:
+		return GetNumericSimilarity(source, target)
+	case sourceKind == KindDate:
+		return GetDateSimilarity(source, target)
+	case sourceKind == KindID:
+		return GetIDSimilarity(source, target)
+	}
+	return GetSimilarity(source, target).Percentage.Media
+}
+
+// GetNumericSimilarity compares the first number found in source and target, return 1 minus
+// their relative difference, or 0 when either has no number. Thousands and decimal separators
+// are told apart, so "1,000", "1.000" and "1000" are the same number and "1.000,5" is 1000.5.
+func GetNumericSimilarity(source, target string) float64 {
+	a, _, ok := extractNumber(source)
+	if !ok {
+		return 0
+	}
+	b, _, ok := extractNumber(target)
+	if !ok {
+		return 0
+	}
+
+	if a == b {
+		return 1
+	}
+	return math.Max(0, 1-math.Abs(a-b)/math.Max(math.Abs(a), math.Abs(b)))
+}
+
+// GetDateSimilarity compares the dates found in source and target, return 1 for the same day
+// whatever the layout, 0.9 when day and month are swapped and otherwise decreasing linearly
+// to 0 for dates a year apart. It return 0 when either has no date.
+func GetDateSimilarity(source, target string) float64 {
+	a, ok := parseDate(source)
+	if !ok {
+		return 0
+	}
+	b, ok := parseDate(target)
+	if !ok {
+		return 0
+	}
+
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	if ay == by && am == bm && ad == bd {
+		return 1
+	}
+	if ay == by && int(am) == bd && ad == int(bm) {
+		return 0.9
+	}
+
+	x := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	y := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	days := math.Abs(x.Sub(y).Hours() / 24)
+	return math.Max(0, 1-days/dateSimilarityDays)
+}
+
+// GetPhoneSimilarity compares the digits of two phone numbers. Numbers that only differ in
+// the country code, or in the national trunk prefix 0, are the same, otherwise the similarity
+// is the Levenshtein one of the digits. It return 0 when either has no digits.
+func GetPhoneSimilarity(source, target string) float64 {
+	a, b := phoneDigits(source), phoneDigits(target)
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(a) >= 7 && len(b)-len(a) <= 3 && strings.HasSuffix(string(b), string(a)) {
+		return 1
+	}
+
+	return editSimilarity(a, b)
+}
+
+// GetIDSimilarity compares two identifiers ignoring case, accents and separators, so
+// "12345678-Z" and "12.345.678z" are the same, with the Levenshtein similarity of the rest.
+// It return 0 when either is empty.
+func GetIDSimilarity(source, target string) float64 {
+	a, b := idRunes(source), idRunes(target)
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	return editSimilarity(a, b)
+}
+
+func editSimilarity(a, b []rune) float64 {
+	d := new(bitParallel).distance(a, b)
+	return 1 - float64(d)/float64(max(len(a), len(b)))
+}
+
+// extractNumber return the first number in str and the text around it.
+func extractNumber(str string) (float64, string, bool) {
+	start := strings.IndexFunc(str, func(r rune) bool { return r >= '0' && r <= '9' })
+	if start < 0 {
+		return 0, str, false
+	}
+
+	end := start
+	for end < len(str) {
+		c := str[end]
+		if c >= '0' && c <= '9' {
+			end++
+			continue
+		}
+		if (c == '.' || c == ',' || c == '\'') && end+1 < len(str) && str[end+1] >= '0' && str[end+1] <= '9' {
+			end++
+			continue
+		}
+		break
+	}
+
+	negative := start > 0 && str[start-1] == '-'
+	if negative {
+		start--
+	}
+
+	number, ok := parseNumber(strings.TrimPrefix(str[start:end], "-"))
+	if !ok {
+		return 0, str, false
+	}
+	if negative {
+		number = -number
+	}
+	return number, str[:start] + str[end:], true
+}
+
+// parseNumber parses digits with thousands and decimal separators. When both '.' and ',' are
+// used the last one is the decimal separator, a single separator followed by three digits is
+// a thousands one and an apostrophe always is.
+func parseNumber(str string) (float64, bool) {
+	str = strings.ReplaceAll(str, "'", "")
+
+	lastDot, lastComma := strings.LastIndexByte(str, '.'), strings.LastIndexByte(str, ',')
+	var decimal byte
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		decimal = '.'
+		if lastComma > lastDot {
+			decimal = ','
+		}
+	case lastDot >= 0 || lastComma >= 0:
+		sep, last := byte('.'), lastDot
+		if lastComma >= 0 {
+			sep, last = ',', lastComma
+		}
+		thousands := strings.Count(str, string(sep)) > 1 ||
+			len(str)-last-1 == 3 && last <= 3 && str[0] != '0'
+		if !thousands {
+			decimal = sep
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c == decimal:
+			b.WriteByte('.')
+		case c >= '0' && c <= '9':
+			b.WriteByte(c)
+		}
+	}
+
+	number, err := strconv.ParseFloat(b.String(), 64)
+	return number, err == nil
+}
+
+// parseDate return the date in str, trying the whole string and then each of its words.
+func parseDate(str string) (time.Time, bool) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return time.Time{}, false
+	}
+
+	if date, ok := parseDateLayouts(str); ok {
+		return date, true
+	}
+	for _, word := range strings.Fields(str) {
+		if date, ok := parseDateLayouts(strings.Trim(word, ",;()")); ok {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseDateLayouts(str string) (time.Time, bool) {
+	date, layout, err := utime.ParseAny(str, dateOptions...)
+	// bare numbers are not taken as seconds since the epoch
+	return date, err == nil && layout != utime.LayoutUnix
+}
+
+// isPhone report whether str only has phone characters, 7 to 15 digits and either a leading
+// + or some grouping, so plain numbers are not taken as phones.
+func isPhone(str string) bool {
+	digits, grouped := 0, false
+	for i, r := range str {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0, r == '(' || r == ')' || r == ' ' || r == '-':
+			grouped = true
+		case r == '.':
+		default:
+			return false
+		}
+	}
+	return grouped && digits >= 7 && digits <= 15
+}
+
+// phoneDigits return the digits of a phone number without the international prefix 00 and,
+// for national numbers, without the trunk prefix 0.
+func phoneDigits(str string) []rune {
+	international := strings.HasPrefix(strings.TrimSpace(str), "+")
+
+	var digits []rune
+	for _, r := range str {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
+		}
+	}
+
+	if !international && len(digits) > 2 && digits[0] == '0' && digits[1] == '0' {
+		return digits[2:]
+	}
+	if !international && len(digits) > 1 && digits[0] == '0' {
+		return digits[1:]
+	}
+	return digits
+}
+
+// isID report whether str is a single code of at least 5 letters and digits, both present,
+// once its separators are removed.
+func isID(str string) bool {
+	runes := idRunes(str)
+	if len(runes) < 5 || strings.ContainsAny(strings.TrimSpace(str), " \t") {
+		return false
+	}
+
+	letters, digits := false, false
+	for _, r := range runes {
+		letters = letters || unicode.IsLetter(r)
+		digits = digits || unicode.IsDigit(r)
+	}
+	return letters && digits
+}
+
+// idRunes return the normalized letters and digits of str.
+func idRunes(str string) []rune {
+	var runes []rune
+	for _, r := range strings.Join(tokenize(str), "") {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
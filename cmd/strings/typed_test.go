@@ -0,0 +1,91 @@
+package strings_test
+
+import (
+	"golibs/cmd/strings"
+	"testing"
+)
+
+func TestDetectKind(t *testing.T) {
+	tests := []struct {
+		str  string
+		want strings.Kind
+	}{
+		{str: "12/03/1985", want: strings.KindDate},
+		{str: "1985-03-12", want: strings.KindDate},
+		{str: "2018-03-20T12:49:10Z", want: strings.KindDate},
+		{str: "born on 1985-03-12", want: strings.KindDate},
+		{str: "+34 912 345 678", want: strings.KindPhone},
+		{str: "(555) 123-4567", want: strings.KindPhone},
+		{str: "1,000", want: strings.KindNumber},
+		{str: "$1,000.50", want: strings.KindNumber},
+		{str: "12 kg", want: strings.KindNumber},
+		{str: "912345678", want: strings.KindNumber},
+		{str: "12345678-Z", want: strings.KindID},
+		{str: "AB-1234", want: strings.KindID},
+		{str: "Reynier", want: strings.KindText},
+		{str: "Calle 23 número 5", want: strings.KindText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if got := strings.DetectKind(tt.str); got != tt.want {
+				t.Errorf("DetectKind(%q) = %v, want %v", tt.str, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedSimilarity(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         func(source, target string) float64
+		source, target string
+		want           float64
+	}{
+		{name: "Number thousands", metric: strings.GetNumericSimilarity, source: "1,000", target: "1000", want: 1},
+		{name: "Number decimal comma", metric: strings.GetNumericSimilarity, source: "1.000,50 €", target: "$1,000.50", want: 1},
+		{name: "Number relative", metric: strings.GetNumericSimilarity, source: "90", target: "100", want: 0.9},
+		{name: "Number missing", metric: strings.GetNumericSimilarity, source: "n/a", target: "100", want: 0},
+		{name: "Date layouts", metric: strings.GetDateSimilarity, source: "12/03/1985", target: "1985-03-12", want: 1},
+		{name: "Date swapped", metric: strings.GetDateSimilarity, source: "03/12/1985", target: "1985-03-12", want: 0.9},
+		{name: "Date days apart", metric: strings.GetDateSimilarity, source: "1985-03-12", target: "1985-03-22", want: 1 - 10.0/365},
+		{name: "Date far", metric: strings.GetDateSimilarity, source: "1985-03-12", target: "2000-03-12", want: 0},
+		{name: "Phone country code", metric: strings.GetPhoneSimilarity, source: "+34 912 345 678", target: "912345678", want: 1},
+		{name: "Phone international prefix", metric: strings.GetPhoneSimilarity, source: "0034 912-345-678", target: "+34 (912) 345 678", want: 1},
+		{name: "Phone trunk prefix", metric: strings.GetPhoneSimilarity, source: "020 7946 0018", target: "+44 20 7946 0018", want: 1},
+		{name: "Phone typo", metric: strings.GetPhoneSimilarity, source: "912 345 678", target: "912 345 679", want: 1 - 1.0/9},
+		{name: "ID separators", metric: strings.GetIDSimilarity, source: "12345678-Z", target: "12.345.678z", want: 1},
+		{name: "ID typo", metric: strings.GetIDSimilarity, source: "12345678Z", target: "12345679Z", want: 1 - 1.0/9},
+		{name: "Typed dates", metric: strings.GetTypedSimilarity, source: "12/03/1985", target: "1985-03-12", want: 1},
+		{name: "Typed numbers", metric: strings.GetTypedSimilarity, source: "1,000", target: "1000", want: 1},
+		{name: "Typed phone and number", metric: strings.GetTypedSimilarity, source: "+34 912 345 678", target: "912345678", want: 1},
+		{name: "Typed text", metric: strings.GetTypedSimilarity, source: "Reynier", target: "Reynier", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metric(tt.source, tt.target); got != tt.want {
+				t.Errorf("similarity(%q, %q) = %v, want %v", tt.source, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareStructs_Typed(t *testing.T) {
+	type person struct {
+		Birth  string `fuzzy:"metric=date"`
+		Phone  string `fuzzy:"metric=phone"`
+		Salary string `fuzzy:"metric=number"`
+	}
+
+	got, err := strings.CompareStructs(
+		person{Birth: "12/03/1985", Phone: "+34 912 345 678", Salary: "1.000"},
+		person{Birth: "1985-03-12", Phone: "912345678", Salary: "1000"},
+	)
+	if err != nil {
+		t.Fatalf("CompareStructs() error = %v", err)
+	}
+	if got.Similarity != 1 {
+		t.Errorf("CompareStructs() = %v, want 1", got.Similarity)
+	}
+}
@@ -0,0 +1,163 @@
+package utime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Errors returned by BusinessHours.
+var (
+	ErrInvalidHours    = errors.New("time: invalid opening hours")
+	ErrNoBusinessHours = errors.New("time: no business hours to reach the deadline")
+)
+
+// deadlineDays limits the days Deadline looks ahead, so a schedule without working days
+// fails instead of looping forever.
+const deadlineDays = 3660
+
+// OpeningHours is a range of the day, as the wall clock time elapsed since midnight.
+type OpeningHours struct {
+	Open  time.Duration
+	Close time.Duration
+}
+
+// BusinessHours measures time only while a business is open, given its opening hours per
+// weekday in its location and, optionally, a calendar whose weekends and holidays are closed.
+//
+// A BusinessHours is safe for concurrent use once configured.
+type BusinessHours struct {
+	location *time.Location
+	calendar *Calendar
+	week     [7][]OpeningHours
+}
+
+// NewBusinessHours returns a BusinessHours without opening hours in the named location.
+// calendar can be nil.
+func NewBusinessHours(location string, calendar *Calendar) (*BusinessHours, error) {
+	loc, err := LoadLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	return &BusinessHours{location: loc, calendar: calendar}, nil
+}
+
+// SetHours sets the opening hours of day, replacing the previous ones. The ranges cannot
+// overlap and must be within the day, no ranges closes the day.
+func (b *BusinessHours) SetHours(day time.Weekday, ranges ...OpeningHours) error {
+	ranges = append([]OpeningHours(nil), ranges...)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Open < ranges[j].Open
+	})
+
+	for i, r := range ranges {
+		if r.Open < 0 || r.Close > 24*time.Hour || r.Open >= r.Close {
+			return fmt.Errorf("%w: %v %v-%v", ErrInvalidHours, day, r.Open, r.Close)
+		}
+		if i > 0 && r.Open < ranges[i-1].Close {
+			return fmt.Errorf("%w: %v ranges overlap", ErrInvalidHours, day)
+		}
+	}
+
+	b.week[day] = ranges
+	return nil
+}
+
+// Location return the location of the opening hours.
+func (b *BusinessHours) Location() *time.Location {
+	return b.location
+}
+
+// IsOpen report whether t is within the opening hours.
+func (b *BusinessHours) IsOpen(t time.Time) bool {
+	for _, r := range b.ranges(t) {
+		if !t.Before(r.open) && t.Before(r.close) {
+			return true
+		}
+	}
+	return false
+}
+
+// Elapsed return the business time between from and to, negative when to is before from.
+func (b *BusinessHours) Elapsed(from, to time.Time) time.Duration {
+	if to.Before(from) {
+		return -b.Elapsed(to, from)
+	}
+
+	var elapsed time.Duration
+	last := civilDate(to.In(b.location).Date())
+	for day := from; !civilDate(day.In(b.location).Date()).After(last); day = b.nextDay(day) {
+		for _, r := range b.ranges(day) {
+			start, end := r.open, r.close
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				elapsed += end.Sub(start)
+			}
+		}
+	}
+	return elapsed
+}
+
+// Deadline return the instant at which d of business time has elapsed since start. A range
+// exhausted exactly at its closing time return that time.
+func (b *BusinessHours) Deadline(start time.Time, d time.Duration) (time.Time, error) {
+	if d <= 0 {
+		return start, nil
+	}
+
+	day := start
+	for i := 0; i < deadlineDays; i, day = i+1, b.nextDay(day) {
+		for _, r := range b.ranges(day) {
+			if !r.close.After(start) {
+				continue
+			}
+			open := r.open
+			if open.Before(start) {
+				open = start
+			}
+			available := r.close.Sub(open)
+			if d <= available {
+				return open.Add(d), nil
+			}
+			d -= available
+		}
+	}
+	return time.Time{}, ErrNoBusinessHours
+}
+
+type openRange struct {
+	open, close time.Time
+}
+
+// ranges return the opening hours of the day of t in the location as instants, none on
+// the weekends and holidays of the calendar.
+func (b *BusinessHours) ranges(t time.Time) []openRange {
+	local := t.In(b.location)
+	if b.calendar != nil && !b.calendar.IsBusinessDay(local) {
+		return nil
+	}
+
+	y, m, d := local.Date()
+	hours := b.week[local.Weekday()]
+	ranges := make([]openRange, len(hours))
+	for i, h := range hours {
+		// time.Date takes the nanoseconds as wall clock time, so DST days keep their hours
+		ranges[i] = openRange{
+			open:  time.Date(y, m, d, 0, 0, 0, int(h.Open), b.location),
+			close: time.Date(y, m, d, 0, 0, 0, int(h.Close), b.location),
+		}
+	}
+	return ranges
+}
+
+// nextDay return the midnight of the day after t in the location.
+func (b *BusinessHours) nextDay(t time.Time) time.Time {
+	y, m, d := t.In(b.location).Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, b.location)
+}
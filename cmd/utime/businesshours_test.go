@@ -0,0 +1,192 @@
+package utime_test
+
+import (
+	"errors"
+	"golibs/cmd/utime"
+	"testing"
+	"time"
+)
+
+// officeHours opens from 9 to 14 and from 15 to 18, Monday to Friday, in Madrid.
+func officeHours(t *testing.T) *utime.BusinessHours {
+	hours, err := utime.NewBusinessHours("Europe/Madrid", presetCalendar(t, "ES"))
+	if err != nil {
+		t.Fatalf("NewBusinessHours() error = %v", err)
+	}
+	for day := time.Monday; day <= time.Friday; day++ {
+		err := hours.SetHours(day,
+			utime.OpeningHours{Open: 15 * time.Hour, Close: 18 * time.Hour},
+			utime.OpeningHours{Open: 9 * time.Hour, Close: 14 * time.Hour},
+		)
+		if err != nil {
+			t.Fatalf("SetHours() error = %v", err)
+		}
+	}
+	return hours
+}
+
+func TestBusinessHours_Elapsed(t *testing.T) {
+	hours := officeHours(t)
+	madrid := hours.Location()
+	newYork, err := utime.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{
+			name: "Same range",
+			from: time.Date(2024, time.April, 2, 10, 0, 0, 0, madrid),
+			to:   time.Date(2024, time.April, 2, 12, 30, 0, 0, madrid),
+			want: 150 * time.Minute,
+		},
+		{
+			name: "Lunch break",
+			from: time.Date(2024, time.April, 2, 13, 0, 0, 0, madrid),
+			to:   time.Date(2024, time.April, 2, 16, 0, 0, 0, madrid),
+			want: 2 * time.Hour,
+		},
+		{
+			name: "Over a holiday and a weekend",
+			from: time.Date(2024, time.March, 28, 16, 0, 0, 0, madrid),
+			to:   time.Date(2024, time.April, 1, 10, 0, 0, 0, madrid),
+			want: 3 * time.Hour,
+		},
+		{
+			name: "Other location",
+			from: time.Date(2024, time.April, 2, 3, 0, 0, 0, newYork),
+			to:   time.Date(2024, time.April, 2, 12, 0, 0, 0, newYork),
+			want: 8 * time.Hour,
+		},
+		{
+			name: "Backwards",
+			from: time.Date(2024, time.April, 2, 12, 30, 0, 0, madrid),
+			to:   time.Date(2024, time.April, 2, 10, 0, 0, 0, madrid),
+			want: -150 * time.Minute,
+		},
+		{
+			name: "Closed",
+			from: time.Date(2024, time.April, 6, 10, 0, 0, 0, madrid),
+			to:   time.Date(2024, time.April, 7, 10, 0, 0, 0, madrid),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hours.Elapsed(tt.from, tt.to); got != tt.want {
+				t.Errorf("Elapsed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBusinessHours_Deadline(t *testing.T) {
+	hours := officeHours(t)
+	madrid := hours.Location()
+
+	tests := []struct {
+		name  string
+		start time.Time
+		d     time.Duration
+		want  time.Time
+	}{
+		{
+			name:  "Same day",
+			start: time.Date(2024, time.April, 2, 10, 0, 0, 0, madrid),
+			d:     2 * time.Hour,
+			want:  time.Date(2024, time.April, 2, 12, 0, 0, 0, madrid),
+		},
+		{
+			name:  "Ends at closing time",
+			start: time.Date(2024, time.March, 28, 16, 0, 0, 0, madrid),
+			d:     2 * time.Hour,
+			want:  time.Date(2024, time.March, 28, 18, 0, 0, 0, madrid),
+		},
+		{
+			name:  "After a holiday and a weekend",
+			start: time.Date(2024, time.March, 28, 16, 0, 0, 0, madrid),
+			d:     3 * time.Hour,
+			want:  time.Date(2024, time.April, 1, 10, 0, 0, 0, madrid),
+		},
+		{
+			name:  "Before opening",
+			start: time.Date(2024, time.April, 1, 7, 0, 0, 0, madrid),
+			d:     time.Hour,
+			want:  time.Date(2024, time.April, 1, 10, 0, 0, 0, madrid),
+		},
+		{
+			name:  "During the lunch break",
+			start: time.Date(2024, time.April, 1, 14, 30, 0, 0, madrid),
+			d:     30 * time.Minute,
+			want:  time.Date(2024, time.April, 1, 15, 30, 0, 0, madrid),
+		},
+		{
+			name:  "A week of work",
+			start: time.Date(2024, time.April, 1, 9, 0, 0, 0, madrid),
+			d:     40 * time.Hour,
+			want:  time.Date(2024, time.April, 5, 18, 0, 0, 0, madrid),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hours.Deadline(tt.start, tt.d)
+			if err != nil {
+				t.Fatalf("Deadline() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Deadline() = %v, want %v", got, tt.want)
+			}
+			if elapsed := hours.Elapsed(tt.start, got); elapsed != tt.d {
+				t.Errorf("Elapsed(start, Deadline()) = %v, want %v", elapsed, tt.d)
+			}
+		})
+	}
+}
+
+func TestBusinessHours_DST(t *testing.T) {
+	hours, err := utime.NewBusinessHours("Europe/Madrid", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hours.SetHours(time.Sunday, utime.OpeningHours{Open: 0, Close: 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	madrid := hours.Location()
+	sunday := time.Date(2024, time.March, 31, 0, 0, 0, 0, madrid)
+	if got := hours.Elapsed(sunday, sunday.AddDate(0, 0, 1)); got != 23*time.Hour {
+		t.Errorf("Elapsed() over the spring forward day = %v, want 23h", got)
+	}
+	if !hours.IsOpen(sunday.Add(5*time.Hour)) || hours.IsOpen(sunday.AddDate(0, 0, 1)) {
+		t.Error("IsOpen() does not follow the opening hours")
+	}
+}
+
+func TestBusinessHours_Errors(t *testing.T) {
+	if _, err := utime.NewBusinessHours("Mars/Olympus", nil); !errors.Is(err, utime.ErrUnknownZone) {
+		t.Errorf("NewBusinessHours() error = %v, want ErrUnknownZone", err)
+	}
+
+	hours, err := utime.NewBusinessHours("UTC", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := [][]utime.OpeningHours{
+		{{Open: 10 * time.Hour, Close: 9 * time.Hour}},
+		{{Open: 9 * time.Hour, Close: 25 * time.Hour}},
+		{{Open: 9 * time.Hour, Close: 14 * time.Hour}, {Open: 13 * time.Hour, Close: 18 * time.Hour}},
+	}
+	for _, ranges := range invalid {
+		if err := hours.SetHours(time.Monday, ranges...); !errors.Is(err, utime.ErrInvalidHours) {
+			t.Errorf("SetHours(%v) error = %v, want ErrInvalidHours", ranges, err)
+		}
+	}
+
+	if _, err := hours.Deadline(time.Now(), time.Hour); !errors.Is(err, utime.ErrNoBusinessHours) {
+		t.Errorf("Deadline() error = %v, want ErrNoBusinessHours", err)
+	}
+}
@@ -0,0 +1,322 @@
+package utime
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNoBusinessDays is returned when a calendar has no business day within businessDayGap
+// days, like one whose whole week is weekend or holiday.
+var ErrNoBusinessDays = errors.New("time: no business days in the calendar")
+
+// businessDayGap limits the days AddBusinessDays looks for the next business day, so a
+// calendar without them fails instead of looping forever.
+const businessDayGap = 3660
+
+// Observance moves a holiday falling on a weekend to a working day.
+type Observance int
+
+const (
+	// ObserveNone keeps the holiday on its date.
+	ObserveNone Observance = iota
+	// ObserveMonday moves the holiday to the next working day that is not already a holiday,
+	// Monday with a Saturday and Sunday weekend, like the substitute days of the UK.
+	ObserveMonday
+	// ObserveNearest moves a holiday on the first day of the weekend to the previous working
+	// day and one on the last day to the next, like the federal holidays of the US.
+	ObserveNearest
+)
+
+// Holiday is a day off of a calendar, at midnight UTC.
+type Holiday struct {
+	Name    string
+	Date    time.Time
+	Observe Observance
+}
+
+// HolidayRule return the holidays of a year. The calendar applies their observance, so the
+// dates are the actual ones.
+type HolidayRule interface {
+	Holidays(year int) []Holiday
+}
+
+// FixedHoliday is a holiday on the same date every year. February 29 only happens in leap years.
+type FixedHoliday struct {
+	Name    string
+	Month   time.Month
+	Day     int
+	Observe Observance
+}
+
+// Holidays implements HolidayRule.
+func (h FixedHoliday) Holidays(year int) []Holiday {
+	date := civilDate(year, h.Month, h.Day)
+	if date.Month() != h.Month {
+		return nil
+	}
+	return []Holiday{{Name: h.Name, Date: date, Observe: h.Observe}}
+}
+
+// EasterHoliday is a holiday Offset days after Easter Sunday, negative for the days before.
+type EasterHoliday struct {
+	Name   string
+	Offset int
+}
+
+// Holidays implements HolidayRule.
+func (h EasterHoliday) Holidays(year int) []Holiday {
+	return []Holiday{{Name: h.Name, Date: Easter(year).AddDate(0, 0, h.Offset)}}
+}
+
+// NthWeekdayHoliday is the Nth Weekday of Month, counted from the end of the month when N is
+// negative, so -1 is the last one.
+type NthWeekdayHoliday struct {
+	Name    string
+	Month   time.Month
+	Weekday time.Weekday
+	N       int
+	Observe Observance
+}
+
+// Holidays implements HolidayRule.
+func (h NthWeekdayHoliday) Holidays(year int) []Holiday {
+	var date time.Time
+	switch {
+	case h.N > 0:
+		first := civilDate(year, h.Month, 1)
+		date = first.AddDate(0, 0, (int(h.Weekday)-int(first.Weekday())+7)%7+(h.N-1)*7)
+	case h.N < 0:
+		last := civilDate(year, h.Month+1, 0)
+		date = last.AddDate(0, 0, -(int(last.Weekday())-int(h.Weekday)+7)%7+(h.N+1)*7)
+	default:
+		return nil
+	}
+
+	if date.Month() != h.Month {
+		return nil
+	}
+	return []Holiday{{Name: h.Name, Date: date, Observe: h.Observe}}
+}
+
+// DateHoliday is a holiday that happens once.
+type DateHoliday struct {
+	Name    string
+	Date    time.Time
+	Observe Observance
+}
+
+// Holidays implements HolidayRule.
+func (h DateHoliday) Holidays(year int) []Holiday {
+	y, m, d := h.Date.Date()
+	if y != year {
+		return nil
+	}
+	return []Holiday{{Name: h.Name, Date: civilDate(y, m, d), Observe: h.Observe}}
+}
+
+// Easter return the date of Easter Sunday in the Gregorian calendar, at midnight UTC.
+func Easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return civilDate(year, time.Month(month), day)
+}
+
+func civilDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// dayKey identifies a calendar day whatever its location.
+func dayKey(t time.Time) int {
+	y, m, d := t.Date()
+	return y*10000 + int(m)*100 + d
+}
+
+// Calendar tells working days from weekends and holidays. The days of the times it receives
+// are the ones in their own location. A Calendar is safe for concurrent use.
+type Calendar struct {
+	Name string
+
+	mu      sync.RWMutex
+	weekend [7]bool
+	rules   []HolidayRule
+	years   map[int]map[int]Holiday
+}
+
+// NewCalendar returns a calendar with the weekend days and holiday rules.
+func NewCalendar(name string, weekend []time.Weekday, rules ...HolidayRule) *Calendar {
+	c := &Calendar{Name: name, rules: rules, years: make(map[int]map[int]Holiday)}
+	for _, day := range weekend {
+		c.weekend[day] = true
+	}
+	return c
+}
+
+// AddRules adds holiday rules to the calendar.
+func (c *Calendar) AddRules(rules ...HolidayRule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules = append(c.rules, rules...)
+	c.years = make(map[int]map[int]Holiday)
+}
+
+// IsWeekend report whether the day of t is a weekend day.
+func (c *Calendar) IsWeekend(t time.Time) bool {
+	return c.weekend[t.Weekday()]
+}
+
+// Holiday return the holiday on the day of t, if any.
+func (c *Calendar) Holiday(t time.Time) (Holiday, bool) {
+	holiday, ok := c.year(t.Year())[dayKey(t)]
+	return holiday, ok
+}
+
+// IsBusinessDay report whether the day of t is neither a weekend day nor a holiday.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	if c.IsWeekend(t) {
+		return false
+	}
+	_, holiday := c.Holiday(t)
+	return !holiday
+}
+
+// Holidays return the holidays observed in year, by date.
+func (c *Calendar) Holidays(year int) []Holiday {
+	var holidays []Holiday
+	for _, holiday := range c.year(year) {
+		holidays = append(holidays, holiday)
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays
+}
+
+// NextBusinessDay return the first business day after the day of t, at the same time of day.
+func (c *Calendar) NextBusinessDay(t time.Time) (time.Time, error) {
+	return c.AddBusinessDays(t, 1)
+}
+
+// PreviousBusinessDay return the last business day before the day of t, at the same time of day.
+func (c *Calendar) PreviousBusinessDay(t time.Time) (time.Time, error) {
+	return c.AddBusinessDays(t, -1)
+}
+
+// AddBusinessDays return t moved n business days, backwards when n is negative, keeping the
+// time of day. Zero return t even when it is not a business day. It fails with
+// ErrNoBusinessDays when there is no business day within businessDayGap days.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	// gap counts the days since the last business day
+	for gap := 0; n > 0; {
+		if gap == businessDayGap {
+			return time.Time{}, ErrNoBusinessDays
+		}
+		t, gap = t.AddDate(0, 0, step), gap+1
+		if c.IsBusinessDay(t) {
+			n, gap = n-1, 0
+		}
+	}
+	return t, nil
+}
+
+// BusinessDaysBetween return the number of business days after the day of from up to the day
+// of to included, negative when to is before from. So AddBusinessDays(from, n) is to when to
+// is a business day.
+func (c *Calendar) BusinessDaysBetween(from, to time.Time) int {
+	to = to.In(from.Location())
+	first := civilDate(from.Date())
+	last := civilDate(to.Date())
+
+	sign := 1
+	if last.Before(first) {
+		first, last, sign = last, first, -1
+	}
+
+	n := 0
+	for day := first.AddDate(0, 0, 1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(day) {
+			n++
+		}
+	}
+	return sign * n
+}
+
+// year return the holidays observed in year by day, computing them the first time.
+func (c *Calendar) year(year int) map[int]Holiday {
+	c.mu.RLock()
+	days, ok := c.years[year]
+	c.mu.RUnlock()
+	if ok {
+		return days
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if days, ok := c.years[year]; ok {
+		return days
+	}
+
+	days = make(map[int]Holiday)
+	// observance can move holidays across the new year
+	for y := year - 1; y <= year+1; y++ {
+		for _, holiday := range c.observed(y) {
+			if holiday.Date.Year() == year {
+				days[dayKey(holiday.Date)] = holiday
+			}
+		}
+	}
+	c.years[year] = days
+	return days
+}
+
+// observed return the holidays of year with their observance applied. It must hold mu.
+func (c *Calendar) observed(year int) []Holiday {
+	var actual, moved []Holiday
+	taken := make(map[int]bool)
+	for _, rule := range c.rules {
+		for _, holiday := range rule.Holidays(year) {
+			if holiday.Observe != ObserveNone && c.weekend[holiday.Date.Weekday()] {
+				moved = append(moved, holiday)
+				continue
+			}
+			if !taken[dayKey(holiday.Date)] {
+				taken[dayKey(holiday.Date)] = true
+				actual = append(actual, holiday)
+			}
+		}
+	}
+
+	sort.SliceStable(moved, func(i, j int) bool {
+		return moved[i].Date.Before(moved[j].Date)
+	})
+	for _, holiday := range moved {
+		step := 1
+		if holiday.Observe == ObserveNearest && !c.weekend[holiday.Date.AddDate(0, 0, -1).Weekday()] {
+			step = -1
+		}
+
+		date := holiday.Date
+		for i := 0; i < 366 && (c.weekend[date.Weekday()] || taken[dayKey(date)]); i++ {
+			date = date.AddDate(0, 0, step)
+		}
+		taken[dayKey(date)] = true
+		holiday.Date = date
+		actual = append(actual, holiday)
+	}
+
+	return actual
+}
@@ -0,0 +1,201 @@
+package utime_test
+
+import (
+	"errors"
+	"golibs/cmd/utime"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func presetCalendar(t *testing.T, name string) *utime.Calendar {
+	calendar, err := utime.PresetCalendar(name)
+	if err != nil {
+		t.Fatalf("PresetCalendar(%q) error = %v", name, err)
+	}
+	return calendar
+}
+
+func TestEaster(t *testing.T) {
+	tests := []struct {
+		year int
+		want time.Time
+	}{
+		{year: 2000, want: date(2000, time.April, 23)},
+		{year: 2024, want: date(2024, time.March, 31)},
+		{year: 2025, want: date(2025, time.April, 20)},
+		{year: 2038, want: date(2038, time.April, 25)},
+	}
+	for _, tt := range tests {
+		if got := utime.Easter(tt.year); !got.Equal(tt.want) {
+			t.Errorf("Easter(%d) = %v, want %v", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestCalendar_Holiday(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar string
+		day      time.Time
+		want     string
+	}{
+		{name: "Good Friday", calendar: "ES", day: date(2024, time.March, 29), want: "Viernes Santo"},
+		{name: "Fixed", calendar: "ES", day: date(2024, time.December, 6), want: "Día de la Constitución"},
+		{name: "Not a holiday", calendar: "ES", day: date(2024, time.April, 1)},
+		{name: "Substitute Christmas", calendar: "GB", day: date(2021, time.December, 27), want: "Christmas Day"},
+		{name: "Substitute Boxing Day", calendar: "GB", day: date(2021, time.December, 28), want: "Boxing Day"},
+		{name: "Boxing Day before Christmas substitute", calendar: "GB", day: date(2022, time.December, 26), want: "Boxing Day"},
+		{name: "Christmas substitute after Boxing Day", calendar: "GB", day: date(2022, time.December, 27), want: "Christmas Day"},
+		{name: "Last Monday", calendar: "US", day: date(2024, time.May, 27), want: "Memorial Day"},
+		{name: "Fourth Thursday", calendar: "US", day: date(2024, time.November, 28), want: "Thanksgiving Day"},
+		{name: "Observed on Monday", calendar: "US", day: date(2021, time.July, 5), want: "Independence Day"},
+		{name: "Observed on Friday of the previous year", calendar: "US", day: date(2021, time.December, 31), want: "New Year's Day"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := presetCalendar(t, tt.calendar).Holiday(tt.day)
+			if ok != (tt.want != "") || got.Name != tt.want {
+				t.Errorf("Holiday(%v) = %q, %v, want %q", tt.day, got.Name, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_BusinessDays(t *testing.T) {
+	es := presetCalendar(t, "ES")
+	madrid, err := utime.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thursday := time.Date(2024, time.March, 28, 17, 30, 0, 0, madrid)
+	if got, err := es.NextBusinessDay(thursday); err != nil || !got.Equal(time.Date(2024, time.April, 1, 17, 30, 0, 0, madrid)) {
+		t.Errorf("NextBusinessDay() = %v, %v", got, err)
+	}
+	if got, err := es.AddBusinessDays(thursday, 5); err != nil || !got.Equal(time.Date(2024, time.April, 5, 17, 30, 0, 0, madrid)) {
+		t.Errorf("AddBusinessDays(5) = %v, %v", got, err)
+	}
+	if got, err := es.AddBusinessDays(thursday, -4); err != nil || !got.Equal(time.Date(2024, time.March, 22, 17, 30, 0, 0, madrid)) {
+		t.Errorf("AddBusinessDays(-4) = %v, %v", got, err)
+	}
+	if got, err := es.PreviousBusinessDay(date(2024, time.April, 1)); err != nil || !got.Equal(date(2024, time.March, 28)) {
+		t.Errorf("PreviousBusinessDay() = %v, %v", got, err)
+	}
+
+	from, to := date(2024, time.December, 20), date(2024, time.December, 31)
+	if got := es.BusinessDaysBetween(from, to); got != 6 {
+		t.Errorf("BusinessDaysBetween() = %d, want 6", got)
+	}
+	if got := es.BusinessDaysBetween(to, from); got != -6 {
+		t.Errorf("BusinessDaysBetween() = %d, want -6", got)
+	}
+	if got, err := es.AddBusinessDays(from, es.BusinessDaysBetween(from, to)); err != nil || !got.Equal(to) {
+		t.Errorf("AddBusinessDays(BusinessDaysBetween()) = %v, %v, want %v", got, err, to)
+	}
+
+	if es.IsBusinessDay(date(2024, time.December, 25)) || es.IsBusinessDay(date(2024, time.December, 28)) {
+		t.Error("IsBusinessDay() = true for a holiday or a weekend day")
+	}
+	if !es.IsBusinessDay(date(2024, time.December, 24)) {
+		t.Error("IsBusinessDay() = false for a working day")
+	}
+}
+
+func TestCalendar_Custom(t *testing.T) {
+	// a Friday and Saturday weekend
+	calendar := utime.NewCalendar("custom", []time.Weekday{time.Friday, time.Saturday},
+		utime.FixedHoliday{Name: "Leap day", Month: time.February, Day: 29},
+		utime.NthWeekdayHoliday{Name: "Second Sunday", Month: time.March, Weekday: time.Sunday, N: 2},
+	)
+	calendar.AddRules(utime.DateHoliday{Name: "Once", Date: date(2024, time.June, 7), Observe: utime.ObserveMonday})
+
+	got := calendar.Holidays(2024)
+	want := []utime.Holiday{
+		{Name: "Leap day", Date: date(2024, time.February, 29)},
+		{Name: "Second Sunday", Date: date(2024, time.March, 10)},
+		{Name: "Once", Date: date(2024, time.June, 9), Observe: utime.ObserveMonday},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Holidays(2024) = %v, want %v", got, want)
+	}
+	if got := calendar.Holidays(2023); len(got) != 1 {
+		t.Errorf("Holidays(2023) = %v, want only the second Sunday", got)
+	}
+	if !calendar.IsWeekend(date(2024, time.June, 7)) || calendar.IsWeekend(date(2024, time.June, 9)) {
+		t.Error("IsWeekend() does not follow the custom weekend")
+	}
+}
+
+func TestLoadCalendars(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calendars.json")
+	content := `[{"name": "AR", "weekend": ["Saturday", "sunday"], "holidays": [
+		{"name": "Carnaval", "type": "easter", "offset": -48},
+		{"name": "Día de la Independencia", "type": "fixed", "month": 7, "day": 9}
+	]}]`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	calendars, err := utime.LoadCalendars(path)
+	if err != nil {
+		t.Fatalf("LoadCalendars() error = %v", err)
+	}
+	if holiday, ok := calendars["AR"].Holiday(date(2024, time.February, 12)); !ok || holiday.Name != "Carnaval" {
+		t.Errorf("Holiday() = %v, %v, want Carnaval", holiday, ok)
+	}
+
+	invalid := []string{
+		`[{"name": "X", "weekend": ["someday"]}]`,
+		`[{"name": "X", "holidays": [{"name": "A", "type": "fixed", "month": 13, "day": 1}]}]`,
+		`[{"name": "X", "holidays": [{"name": "A", "type": "nth", "month": 1, "weekday": "monday"}]}]`,
+		`[{"name": "X", "holidays": [{"name": "A", "type": "fixed", "month": 1, "day": 1, "observe": "friday"}]}]`,
+		`[{"name": "X", "holidays": [{"name": "A", "type": "lunar"}]}]`,
+	}
+	for _, content := range invalid {
+		if _, err := utime.ReadCalendars(strings.NewReader(content)); !errors.Is(err, utime.ErrInvalidHoliday) {
+			t.Errorf("ReadCalendars(%s) error = %v, want ErrInvalidHoliday", content, err)
+		}
+	}
+}
+
+func TestPresetCalendar(t *testing.T) {
+	if got, want := utime.PresetCalendars(), []string{"ES", "GB", "US"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PresetCalendars() = %v, want %v", got, want)
+	}
+	if _, err := utime.PresetCalendar("XX"); !errors.Is(err, utime.ErrUnknownCalendar) {
+		t.Errorf("PresetCalendar() error = %v, want ErrUnknownCalendar", err)
+	}
+}
+
+func TestCalendar_NoBusinessDays(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	var rules []utime.HolidayRule
+	for _, weekday := range weekdays {
+		for nth := 1; nth <= 5; nth++ {
+			for month := time.January; month <= time.December; month++ {
+				rules = append(rules, utime.NthWeekdayHoliday{Name: "Closed", Month: month, Weekday: weekday, N: nth})
+			}
+		}
+	}
+	closed := utime.NewCalendar("Closed", []time.Weekday{time.Saturday, time.Sunday}, rules...)
+	weekend := utime.NewCalendar("Weekend", append(weekdays, time.Saturday, time.Sunday))
+
+	for _, calendar := range []*utime.Calendar{closed, weekend} {
+		for _, n := range []int{1, -1} {
+			if _, err := calendar.AddBusinessDays(date(2024, time.March, 28), n); !errors.Is(err, utime.ErrNoBusinessDays) {
+				t.Errorf("%s AddBusinessDays(%d) error = %v, want ErrNoBusinessDays", calendar.Name, n, err)
+			}
+		}
+	}
+	if got, err := closed.AddBusinessDays(date(2024, time.March, 28), 0); err != nil || !got.Equal(date(2024, time.March, 28)) {
+		t.Errorf("AddBusinessDays(0) = %v, %v", got, err)
+	}
+}
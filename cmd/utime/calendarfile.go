@@ -0,0 +1,198 @@
+package utime
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Errors returned when reading calendars.
+var (
+	ErrUnknownCalendar = errors.New("time: unknown calendar")
+	ErrInvalidHoliday  = errors.New("time: invalid holiday rule")
+)
+
+// presets are the national holidays of Spain (ES), England and Wales (GB) and the federal
+// ones of the United States (US), in the format of ReadCalendars.
+//
+//go:embed calendars.json
+var presets []byte
+
+// The calendar files are a JSON array of calendars like
+//
+//	{
+//	  "name": "GB",
+//	  "weekend": ["saturday", "sunday"],
+//	  "holidays": [
+//	    {"name": "Christmas Day", "type": "fixed", "month": 12, "day": 25, "observe": "monday"},
+//	    {"name": "Good Friday", "type": "easter", "offset": -2},
+//	    {"name": "Spring bank holiday", "type": "nth", "month": 5, "weekday": "monday", "n": -1},
+//	    {"name": "Jubilee", "type": "date", "date": "2022-06-03"}
+//	  ]
+//	}
+//
+// observe is none, the default, monday or nearest.
+type calendarFile struct {
+	Name     string        `json:"name"`
+	Weekend  []string      `json:"weekend"`
+	Holidays []holidayFile `json:"holidays"`
+}
+
+type holidayFile struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Month   int    `json:"month"`
+	Day     int    `json:"day"`
+	Offset  int    `json:"offset"`
+	Weekday string `json:"weekday"`
+	N       int    `json:"n"`
+	Date    string `json:"date"`
+	Observe string `json:"observe"`
+}
+
+var observances = map[string]Observance{
+	"":        ObserveNone,
+	"none":    ObserveNone,
+	"monday":  ObserveMonday,
+	"nearest": ObserveNearest,
+}
+
+// ReadCalendars reads a JSON array of calendars, returning them by name.
+func ReadCalendars(r io.Reader) (map[string]*Calendar, error) {
+	var files []calendarFile
+	if err := json.NewDecoder(r).Decode(&files); err != nil {
+		return nil, err
+	}
+
+	calendars := make(map[string]*Calendar, len(files))
+	for _, file := range files {
+		calendar, err := file.calendar()
+		if err != nil {
+			return nil, err
+		}
+		calendars[file.Name] = calendar
+	}
+	return calendars, nil
+}
+
+// LoadCalendars reads the calendars of the JSON file at path.
+func LoadCalendars(path string) (map[string]*Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadCalendars(file)
+}
+
+// presetFiles holds the presets by name, read once and checked when the package loads.
+var presetFiles = readPresets()
+
+// readPresets reads the presets, panicking when the embedded file is broken.
+func readPresets() map[string]calendarFile {
+	var files []calendarFile
+	if err := json.Unmarshal(presets, &files); err != nil {
+		panic("utime: invalid preset calendars: " + err.Error())
+	}
+
+	byName := make(map[string]calendarFile, len(files))
+	for _, file := range files {
+		if _, err := file.calendar(); err != nil {
+			panic("utime: invalid preset calendars: " + err.Error())
+		}
+		byName[file.Name] = file
+	}
+	return byName
+}
+
+// PresetCalendar returns a new calendar of the presets: ES, GB or US.
+func PresetCalendar(name string) (*Calendar, error) {
+	file, ok := presetFiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCalendar, name)
+	}
+	return file.calendar()
+}
+
+// PresetCalendars return the names of the preset calendars.
+func PresetCalendars() []string {
+	names := make([]string, 0, len(presetFiles))
+	for name := range presetFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f calendarFile) calendar() (*Calendar, error) {
+	var weekend []time.Weekday
+	for _, name := range f.Weekend {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: calendar %q: unknown weekday %q", ErrInvalidHoliday, f.Name, name)
+		}
+		weekend = append(weekend, day)
+	}
+
+	calendar := NewCalendar(f.Name, weekend)
+	for _, h := range f.Holidays {
+		rule, err := h.rule()
+		if err != nil {
+			return nil, fmt.Errorf("%w: calendar %q: holiday %q: %v", ErrInvalidHoliday, f.Name, h.Name, err)
+		}
+		calendar.AddRules(rule)
+	}
+	return calendar, nil
+}
+
+func (h holidayFile) rule() (HolidayRule, error) {
+	observe, ok := observances[h.Observe]
+	if !ok {
+		return nil, fmt.Errorf("unknown observance %q", h.Observe)
+	}
+	if (h.Type == "fixed" || h.Type == "nth") && (h.Month < 1 || h.Month > 12) {
+		return nil, fmt.Errorf("month %d out of range", h.Month)
+	}
+
+	switch h.Type {
+	case "fixed":
+		if h.Day < 1 || h.Day > 31 {
+			return nil, fmt.Errorf("day %d out of range", h.Day)
+		}
+		return FixedHoliday{Name: h.Name, Month: time.Month(h.Month), Day: h.Day, Observe: observe}, nil
+	case "easter":
+		return EasterHoliday{Name: h.Name, Offset: h.Offset}, nil
+	case "nth":
+		weekday, ok := parseWeekday(h.Weekday)
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", h.Weekday)
+		}
+		if h.N == 0 || h.N < -5 || h.N > 5 {
+			return nil, fmt.Errorf("n %d out of range", h.N)
+		}
+		return NthWeekdayHoliday{Name: h.Name, Month: time.Month(h.Month), Weekday: weekday, N: h.N, Observe: observe}, nil
+	case "date":
+		date, err := time.Parse("2006-01-02", h.Date)
+		if err != nil {
+			return nil, err
+		}
+		return DateHoliday{Name: h.Name, Date: date, Observe: observe}, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", h.Type)
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
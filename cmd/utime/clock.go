@@ -0,0 +1,296 @@
+package utime
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of the code that needs to be tested without waiting, use
+// RealClock in production and a FakeClock in tests.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+	Sleep(d time.Duration)
+}
+
+// Timer is the Clock counterpart of time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the Clock counterpart of time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// RealClock is the Clock of the time package.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct{ *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// FakeClock is a Clock whose time only moves when Advance or Set are called, firing then the
+// timers, tickers and sleeps due in chronological order. The channels hold one value and the
+// ticks not received are dropped. As in the time package since Go 1.23, no stale value is
+// received after Stop or Reset.
+//
+// A FakeClock is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters waiterHeap
+	// seq numbers the scheduled waiters, to fire them in that order on ties.
+	seq uint64
+}
+
+// NewFakeClock returns a FakeClock stopped at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now return the time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since return the time elapsed on the clock since t.
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// After return a channel receiving the time of the clock once it advances d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer returns a Timer firing once the clock advances d.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1), index: -1}
+	t.Reset(d)
+	return t
+}
+
+// NewTicker returns a Ticker firing every time the clock advances d. It panics if d is not
+// positive, like time.NewTicker.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	t := fakeTicker{&fakeTimer{clock: c, c: make(chan time.Time, 1), index: -1}}
+	t.Reset(d)
+	return t
+}
+
+// Sleep blocks until the clock advances d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-c.After(d)
+}
+
+// Advance moves the clock forward d, firing the timers and tickers due in between.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := c.now.Add(d)
+	for len(c.waiters) > 0 && !c.waiters[0].when.After(target) {
+		t := c.waiters[0]
+		c.now = t.when
+
+		select {
+		case t.c <- t.when:
+		default:
+		}
+
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+			heap.Fix(&c.waiters, 0)
+		} else {
+			heap.Pop(&c.waiters)
+		}
+	}
+	if target.After(c.now) {
+		c.now = target
+	}
+	c.changed.Broadcast()
+}
+
+// Set moves the clock to now, firing the timers and tickers due in between. Setting an
+// earlier time does not fire anything.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	if !now.After(c.now) {
+		c.now = now
+		c.mu.Unlock()
+		return
+	}
+	d := now.Sub(c.now)
+	c.mu.Unlock()
+
+	c.Advance(d)
+}
+
+// Waiters return the number of active timers, tickers and sleeps.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until there are at least n active timers, tickers and sleeps, so a test
+// can advance the clock once the goroutine under test is waiting on it.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+// schedule adds t to the waiters. It must hold mu.
+func (c *FakeClock) schedule(t *fakeTimer) {
+	t.seq = c.seq
+	c.seq++
+	heap.Push(&c.waiters, t)
+}
+
+// remove deletes t from the waiters, reporting whether it was there. It must hold mu.
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	if t.index < 0 {
+		return false
+	}
+	heap.Remove(&c.waiters, t.index)
+	return true
+}
+
+// waiterHeap is a heap of the waiters by firing time, in scheduling order on ties.
+type waiterHeap []*fakeTimer
+
+func (h waiterHeap) Len() int { return len(h) }
+
+func (h waiterHeap) Less(i, j int) bool {
+	if h[i].when.Equal(h[j].when) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].when.Before(h[j].when)
+}
+
+func (h waiterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *waiterHeap) Push(x any) {
+	t := x.(*fakeTimer)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *waiterHeap) Pop() any {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	t.index = -1
+	return t
+}
+
+type fakeTimer struct {
+	clock  *FakeClock
+	c      chan time.Time
+	when   time.Time
+	period time.Duration
+	// index is the position in the waiters heap, -1 when not waiting, and seq the scheduling
+	// order.
+	index int
+	seq   uint64
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.drain()
+	return t.clock.remove(t)
+}
+
+// drain discards a value not received yet. It must hold the clock mu.
+func (t *fakeTimer) drain() {
+	select {
+	case <-t.c:
+	default:
+	}
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	active := c.remove(t)
+	t.drain()
+
+	t.when = c.now.Add(d)
+	c.schedule(t)
+	c.changed.Broadcast()
+	periodic := t.period != 0
+	c.mu.Unlock()
+
+	// a timer already due fires right away, as in the time package
+	if d <= 0 && !periodic {
+		c.Advance(0)
+	}
+	return active
+}
+
+type fakeTicker struct{ *fakeTimer }
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	t.clock.mu.Lock()
+	t.period = d
+	t.clock.mu.Unlock()
+	t.fakeTimer.Reset(d)
+}
@@ -0,0 +1,198 @@
+package utime_test
+
+import (
+	"golibs/cmd/utime"
+	"sync"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, time.July, 23, 10, 0, 0, 0, time.UTC)
+
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeClock_Timer(t *testing.T) {
+	clock := utime.NewFakeClock(epoch)
+	timer := clock.NewTimer(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	if _, ok := received(timer.C()); ok {
+		t.Fatal("timer fired before its time")
+	}
+
+	clock.Advance(time.Second)
+	if got, ok := received(timer.C()); !ok || !got.Equal(epoch.Add(time.Minute)) {
+		t.Fatalf("timer fired = %v, %v, want %v", got, ok, epoch.Add(time.Minute))
+	}
+	if timer.Stop() {
+		t.Error("Stop() = true for a fired timer")
+	}
+
+	if timer.Reset(time.Hour) {
+		t.Error("Reset() = true for a fired timer")
+	}
+	if !timer.Stop() {
+		t.Error("Stop() = false for an active timer")
+	}
+	clock.Advance(2 * time.Hour)
+	if _, ok := received(timer.C()); ok {
+		t.Error("stopped timer fired")
+	}
+	if got := clock.Now(); !got.Equal(epoch.Add(2*time.Hour + time.Minute)) {
+		t.Errorf("Now() = %v", got)
+	}
+}
+
+func TestFakeClock_Ticker(t *testing.T) {
+	clock := utime.NewFakeClock(epoch)
+	ticker := clock.NewTicker(10 * time.Second)
+	after := clock.After(25 * time.Second)
+
+	var ticks []time.Time
+	for i := 0; i < 3; i++ {
+		clock.Advance(10 * time.Second)
+		if tick, ok := received(ticker.C()); ok {
+			ticks = append(ticks, tick)
+		}
+	}
+	if len(ticks) != 3 || !ticks[2].Equal(epoch.Add(30*time.Second)) {
+		t.Errorf("ticks = %v", ticks)
+	}
+	if got, ok := received(after); !ok || !got.Equal(epoch.Add(25*time.Second)) {
+		t.Errorf("After() = %v, %v", got, ok)
+	}
+
+	// ticks not received are dropped
+	clock.Advance(time.Minute)
+	if _, ok := received(ticker.C()); !ok {
+		t.Error("ticker did not fire")
+	}
+	if _, ok := received(ticker.C()); ok {
+		t.Error("ticker kept more than one tick")
+	}
+
+	ticker.Reset(time.Hour)
+	clock.Advance(59 * time.Minute)
+	if _, ok := received(ticker.C()); ok {
+		t.Error("ticker fired before the new period")
+	}
+	ticker.Stop()
+	if clock.Waiters() != 0 {
+		t.Errorf("Waiters() = %d, want 0", clock.Waiters())
+	}
+}
+
+func TestFakeClock_Sleep(t *testing.T) {
+	clock := utime.NewFakeClock(epoch)
+
+	var wg sync.WaitGroup
+	var woke time.Time
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		clock.Sleep(time.Hour)
+		woke = clock.Now()
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	wg.Wait()
+
+	if !woke.Equal(epoch.Add(time.Hour)) {
+		t.Errorf("Sleep() woke at %v", woke)
+	}
+	if got := clock.Since(epoch); got != time.Hour {
+		t.Errorf("Since() = %v, want 1h", got)
+	}
+}
+
+func TestFakeClock_Set(t *testing.T) {
+	clock := utime.NewFakeClock(epoch)
+	timer := clock.NewTimer(24 * time.Hour)
+
+	clock.Set(epoch.AddDate(0, 0, 2))
+	if got, ok := received(timer.C()); !ok || !got.Equal(epoch.AddDate(0, 0, 1)) {
+		t.Errorf("timer fired = %v, %v", got, ok)
+	}
+	if got := clock.Now(); !got.Equal(epoch.AddDate(0, 0, 2)) {
+		t.Errorf("Now() = %v", got)
+	}
+}
+
+func TestFakeClock_ManyWaiters(t *testing.T) {
+	clock := utime.NewFakeClock(epoch)
+
+	timers := make([]utime.Timer, 50)
+	for i := range timers {
+		timers[i] = clock.NewTimer(time.Duration(i*7%50+1) * time.Minute)
+	}
+	// stop and move some from the middle of the waiters
+	for i := 0; i < len(timers); i += 5 {
+		timers[i].Stop()
+	}
+	for i := 1; i < len(timers); i += 5 {
+		timers[i].Reset(time.Duration(i) * time.Second)
+	}
+	ticker := clock.NewTicker(time.Second)
+	if got, want := clock.Waiters(), len(timers)-len(timers)/5+1; got != want {
+		t.Fatalf("Waiters() = %d, want %d", got, want)
+	}
+
+	clock.Advance(time.Hour)
+	for i, timer := range timers {
+		got, ok := received(timer.C())
+		want := epoch.Add(time.Duration(i*7%50+1) * time.Minute)
+		switch i % 5 {
+		case 0:
+			if ok {
+				t.Errorf("stopped timer %d fired at %v", i, got)
+			}
+			continue
+		case 1:
+			want = epoch.Add(time.Duration(i) * time.Second)
+		}
+		if !ok || !got.Equal(want) {
+			t.Errorf("timer %d fired = %v, %v, want %v", i, got, ok, want)
+		}
+	}
+	// the first tick is kept and the next ones dropped
+	if got, ok := received(ticker.C()); !ok || !got.Equal(epoch.Add(time.Second)) {
+		t.Errorf("ticker fired = %v, %v, want %v", got, ok, epoch.Add(time.Second))
+	}
+	if clock.Waiters() != 1 {
+		t.Errorf("Waiters() = %d, want 1", clock.Waiters())
+	}
+}
+
+func TestRealClock(t *testing.T) {
+	var clock utime.Clock = utime.RealClock
+
+	start := clock.Now()
+	timer := clock.NewTimer(time.Millisecond)
+	<-timer.C()
+	if clock.Since(start) < time.Millisecond {
+		t.Errorf("Since() = %v, want at least 1ms", clock.Since(start))
+	}
+
+	ticker := clock.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+}
+
+func BenchmarkFakeClock_Advance(b *testing.B) {
+	clock := utime.NewFakeClock(epoch)
+	for i := 1; i <= 100; i++ {
+		clock.NewTicker(time.Duration(i) * time.Second)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		clock.Advance(time.Minute)
+	}
+}
@@ -0,0 +1,499 @@
+// Package cron parses cron expressions and computes their fire times.
+//
+// Expressions have five fields, minute hour day-of-month month day-of-week, or six with the
+// seconds first. Fields accept *, values, ranges a-b, steps */n, a-b/n and a/n, lists
+// separated by commas, and the names JAN-DEC and SUN-SAT; ? is * for the days and 7 is Sunday.
+// When both days fields are restricted a day matches either of them, as in Vixie cron.
+//
+// The descriptors @yearly (@annually), @monthly, @weekly, @daily (@midnight), @hourly and
+// @every <duration> are also accepted, and a TZ=<location> or CRON_TZ=<location> prefix sets
+// the location of the schedule.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"golibs/cmd/utime"
+)
+
+// ErrSyntax is matched by every SyntaxError.
+var ErrSyntax = errors.New("cron: syntax error")
+
+// SyntaxError reports an invalid expression and the byte offset of the error in it.
+type SyntaxError struct {
+	Expr   string
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("cron: %s at column %d of %q", e.Msg, e.Offset+1, e.Expr)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrSyntax
+}
+
+// searchYears limits the search of fire times, enough for February 29 across a century.
+const searchYears = 9
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondField = field{name: "second", min: 0, max: 59}
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// Schedule is a parsed cron expression. Its fire times are computed on the wall clock of its
+// location, or of the times it receives when it has none. Every wall clock time fires once:
+// the times skipped when the clocks go forward fire at the transition and the ones repeated
+// when they go back fire on their first occurrence.
+//
+// A Schedule is safe for concurrent use.
+type Schedule struct {
+	expr     string
+	location *time.Location
+	every    time.Duration
+
+	seconds, minutes, hours, dom, months, dow uint64
+	// domAny and dowAny tell the days fields starting with *, like */2, to combine them like
+	// Vixie cron.
+	domAny, dowAny bool
+}
+
+// Parse parses a cron expression.
+func Parse(expr string) (*Schedule, error) {
+	s := &Schedule{expr: expr}
+
+	spec, offset := expr, 0
+	skip := func(n int) {
+		offset += n
+		spec = spec[n:]
+	}
+	skip(len(spec) - len(strings.TrimLeft(spec, " \t")))
+
+	for _, prefix := range []string{"TZ=", "CRON_TZ="} {
+		if !strings.HasPrefix(spec, prefix) {
+			continue
+		}
+		end := strings.IndexAny(spec, " \t")
+		if end < 0 {
+			return nil, &SyntaxError{Expr: expr, Offset: offset + len(spec), Msg: "missing fields after location"}
+		}
+		location, err := utime.LoadLocation(spec[len(prefix):end])
+		if err != nil {
+			return nil, err
+		}
+		s.location = location
+		skip(end)
+		skip(len(spec) - len(strings.TrimLeft(spec, " \t")))
+		break
+	}
+
+	spec = strings.TrimRight(spec, " \t")
+	if strings.HasPrefix(spec, "@") {
+		return s.parseDescriptor(spec, offset)
+	}
+	return s, s.parseFields(spec, offset)
+}
+
+// MustParse is like Parse but panics if the expression cannot be parsed.
+func MustParse(expr string) *Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *Schedule) parseDescriptor(spec string, offset int) (*Schedule, error) {
+	// the descriptors are case insensitive, the duration of @every is not
+	end := strings.IndexAny(spec, " \t")
+	if end < 0 {
+		end = len(spec)
+	}
+	if strings.EqualFold(spec[:end], "@every") {
+		value := strings.TrimLeft(spec[end:], " \t")
+		valueOffset := offset + len(spec) - len(value)
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, &SyntaxError{Expr: s.expr, Offset: valueOffset, Msg: fmt.Sprintf("invalid duration %q", value)}
+		}
+		if d <= 0 {
+			return nil, &SyntaxError{Expr: s.expr, Offset: valueOffset, Msg: fmt.Sprintf("duration %v is not positive", d)}
+		}
+		s.every = d
+		return s, nil
+	}
+
+	fields, ok := descriptors[strings.ToLower(spec)]
+	if !ok {
+		return nil, &SyntaxError{Expr: s.expr, Offset: offset, Msg: fmt.Sprintf("unknown descriptor %q", spec)}
+	}
+	if err := s.parseFields(fields, 0); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Schedule) parseFields(spec string, offset int) error {
+	type token struct {
+		text   string
+		offset int
+	}
+	var tokens []token
+	for i := 0; i < len(spec); {
+		if spec[i] == ' ' || spec[i] == '\t' {
+			i++
+			continue
+		}
+		end := i
+		for end < len(spec) && spec[end] != ' ' && spec[end] != '\t' {
+			end++
+		}
+		tokens = append(tokens, token{text: spec[i:end], offset: offset + i})
+		i = end
+	}
+
+	switch {
+	case len(tokens) < 5:
+		return &SyntaxError{Expr: s.expr, Offset: offset + len(spec), Msg: fmt.Sprintf("expected 5 or 6 fields, found %d", len(tokens))}
+	case len(tokens) > 6:
+		return &SyntaxError{Expr: s.expr, Offset: tokens[6].offset, Msg: "expected 5 or 6 fields, found more"}
+	case len(tokens) == 5:
+		tokens = append([]token{{text: "0", offset: -1}}, tokens...)
+	}
+
+	targets := []struct {
+		field field
+		set   *uint64
+	}{
+		{secondField, &s.seconds},
+		{minuteField, &s.minutes},
+		{hourField, &s.hours},
+		{domField, &s.dom},
+		{monthField, &s.months},
+		{dowField, &s.dow},
+	}
+	for i, target := range targets {
+		set, err := s.parseField(tokens[i].text, tokens[i].offset, target.field)
+		if err != nil {
+			return err
+		}
+		*target.set = set
+	}
+
+	// 7 is also Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domAny = isAny(tokens[3].text)
+	s.dowAny = isAny(tokens[5].text)
+	return nil
+}
+
+// isAny report whether a days field is unrestricted for Vixie cron, which only looks at its
+// first character.
+func isAny(text string) bool {
+	return strings.HasPrefix(text, "*") || text == "?"
+}
+
+// parseField parses a comma separated list of the field into a set of bits.
+func (s *Schedule) parseField(text string, offset int, f field) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(text, ",") {
+		values, err := s.parsePart(part, offset, f)
+		if err != nil {
+			return 0, err
+		}
+		set |= values
+		offset += len(part) + 1
+	}
+	return set, nil
+}
+
+// parsePart parses *, ?, a value, a range or any of them with a step.
+func (s *Schedule) parsePart(part string, offset int, f field) (uint64, error) {
+	fail := func(at int, format string, args ...any) (uint64, error) {
+		return 0, &SyntaxError{Expr: s.expr, Offset: offset + at, Msg: f.name + ": " + fmt.Sprintf(format, args...)}
+	}
+
+	if part == "" {
+		return fail(0, "empty value")
+	}
+
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+	low, high := f.min, f.max
+
+	switch {
+	case rangePart == "*" || rangePart == "?" && (f.name == domField.name || f.name == dowField.name):
+		if f.name == dowField.name {
+			high = 6
+		}
+	default:
+		lowText, highText, isRange := strings.Cut(rangePart, "-")
+		var err error
+		if low, err = f.value(lowText); err != nil {
+			return fail(0, "%v", err)
+		}
+		high = low
+		if isRange {
+			if high, err = f.value(highText); err != nil {
+				return fail(len(lowText)+1, "%v", err)
+			}
+			if high < low {
+				return fail(0, "range %s is backwards", rangePart)
+			}
+		} else if hasStep {
+			// a/n goes from a to the end of the field
+			high = f.max
+			if f.name == dowField.name {
+				high = 6
+			}
+		}
+	}
+
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n <= 0 {
+			return fail(len(rangePart)+1, "invalid step %q", stepPart)
+		}
+		step = n
+	}
+
+	var set uint64
+	for v := low; v <= high; v += step {
+		set |= 1 << uint(v)
+	}
+	return set, nil
+}
+
+// value parses a number or a name of the field, checking its range.
+func (f field) value(text string) (int, error) {
+	if v, ok := f.names[strings.ToLower(text)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", text)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// String return the expression the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Location return the location of the schedule, nil when it uses the one of the times.
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// In returns a copy of the schedule whose fire times are computed in location.
+func (s *Schedule) In(location *time.Location) *Schedule {
+	c := *s
+	c.location = location
+	return &c
+}
+
+// Next return the first fire time after t, or the zero time when there is none.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+
+	loc := s.locationOf(t)
+	wall := wallClock(t.In(loc)).Truncate(time.Second)
+	limit := wall.Year() + searchYears
+
+	for wall = s.nextWall(wall, limit); !wall.IsZero(); wall = s.nextWall(wall.Add(time.Second), limit) {
+		if fire := resolve(wall, loc); fire.After(t) {
+			return fire
+		}
+	}
+	return time.Time{}
+}
+
+// Prev return the last fire time before t, or the zero time when there is none.
+func (s *Schedule) Prev(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(-s.every)
+	}
+
+	loc := s.locationOf(t)
+	// a fire time on the wall clock of t can be before it when t is in a repeated hour
+	wall := wallClock(t.In(loc)).Truncate(time.Second)
+	limit := wall.Year() - searchYears
+
+	for wall = s.prevWall(wall, limit); !wall.IsZero(); wall = s.prevWall(wall.Add(-time.Second), limit) {
+		if fire := resolve(wall, loc); fire.Before(t) {
+			return fire
+		}
+	}
+	return time.Time{}
+}
+
+// NextN return the next n fire times after t, fewer if the schedule ends.
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	var times []time.Time
+	for i := 0; i < n; i++ {
+		if t = s.Next(t); t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// PrevN return the previous n fire times before t, the latest first.
+func (s *Schedule) PrevN(t time.Time, n int) []time.Time {
+	var times []time.Time
+	for i := 0; i < n; i++ {
+		if t = s.Prev(t); t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+func (s *Schedule) locationOf(t time.Time) *time.Location {
+	if s.location != nil {
+		return s.location
+	}
+	return t.Location()
+}
+
+// dayMatches combines the days fields: both must match when one starts with *, either otherwise.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// nextWall return the first wall clock time from w matching the fields, before the year limit.
+// Wall clock times are UTC times with the fields of the local ones.
+func (s *Schedule) nextWall(w time.Time, limit int) time.Time {
+	for w.Year() <= limit {
+		y, m, d := w.Date()
+		switch {
+		case s.months&(1<<uint(m)) == 0:
+			w = time.Date(y, m+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(w):
+			w = time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+		case s.hours&(1<<uint(w.Hour())) == 0:
+			w = w.Truncate(time.Hour).Add(time.Hour)
+		case s.minutes&(1<<uint(w.Minute())) == 0:
+			w = w.Truncate(time.Minute).Add(time.Minute)
+		case s.seconds&(1<<uint(w.Second())) == 0:
+			w = w.Add(time.Duration(nextBit(s.seconds, w.Second())-w.Second()) * time.Second)
+		default:
+			return w
+		}
+	}
+	return time.Time{}
+}
+
+// prevWall return the last wall clock time up to w matching the fields, after the year limit.
+func (s *Schedule) prevWall(w time.Time, limit int) time.Time {
+	for w.Year() >= limit {
+		y, m, d := w.Date()
+		switch {
+		case s.months&(1<<uint(m)) == 0:
+			w = time.Date(y, m, 1, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		case !s.dayMatches(w):
+			w = time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		case s.hours&(1<<uint(w.Hour())) == 0:
+			w = w.Truncate(time.Hour).Add(-time.Second)
+		case s.minutes&(1<<uint(w.Minute())) == 0:
+			w = w.Truncate(time.Minute).Add(-time.Second)
+		case s.seconds&(1<<uint(w.Second())) == 0:
+			w = w.Add(-time.Second)
+		default:
+			return w
+		}
+	}
+	return time.Time{}
+}
+
+// nextBit return the first bit set in set after from, or 60 to move to the next minute.
+func nextBit(set uint64, from int) int {
+	rest := set >> uint(from+1) << uint(from+1)
+	if rest == 0 {
+		return 60
+	}
+	return bits.TrailingZeros64(rest)
+}
+
+// wallClock return the UTC time with the fields of t.
+func wallClock(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// resolve return the first instant whose wall clock in loc is wall, or the end of the gap
+// when the clocks skipped it.
+func resolve(wall time.Time, loc *time.Location) time.Time {
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	earliest := time.Time{}
+	for _, offset := range []int{before, after} {
+		t := wall.Add(-time.Duration(offset) * time.Second)
+		if _, actual := t.In(loc).Zone(); actual == offset && (earliest.IsZero() || t.Before(earliest)) {
+			earliest = t
+		}
+	}
+	if !earliest.IsZero() {
+		return earliest.In(loc)
+	}
+
+	// the transition is between the instants of the wall clock in both offsets
+	low := wall.Add(-time.Duration(max(before, after)) * time.Second)
+	high := wall.Add(-time.Duration(min(before, after)) * time.Second)
+	for high.Sub(low) > time.Second {
+		mid := low.Add(high.Sub(low) / 2).Truncate(time.Second)
+		if _, offset := mid.In(loc).Zone(); offset == before {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return high.In(loc)
+}
@@ -0,0 +1,243 @@
+package cron_test
+
+import (
+	"errors"
+	"golibs/cmd/utime"
+	"golibs/cmd/utime/cron"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func location(t *testing.T, name string) *time.Location {
+	loc, err := utime.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expr       string
+		wantOffset int
+		wantMsg    string
+	}{
+		{expr: "61 * * * *", wantOffset: 0, wantMsg: "minute: value 61 out of range 0-59"},
+		{expr: "* * 5-1 * *", wantOffset: 4, wantMsg: "day of month: range 5-1 is backwards"},
+		{expr: "*/0 * * * *", wantOffset: 2, wantMsg: `minute: invalid step "0"`},
+		{expr: "* * * * MON,FOO", wantOffset: 12, wantMsg: `day of week: invalid value "FOO"`},
+		{expr: "0 0 * 1-13 *", wantOffset: 8, wantMsg: "month: value 13 out of range 1-12"},
+		{expr: "0 ? * * *", wantOffset: 2, wantMsg: `hour: invalid value "?"`},
+		{expr: "* * * * * * *", wantOffset: 12, wantMsg: "expected 5 or 6 fields, found more"},
+		{expr: "* * *", wantOffset: 5, wantMsg: "expected 5 or 6 fields, found 3"},
+		{expr: "@every -1s", wantOffset: 7, wantMsg: "duration -1s is not positive"},
+		{expr: "@every soon", wantOffset: 7, wantMsg: `invalid duration "soon"`},
+		{expr: "TZ=UTC @fortnightly", wantOffset: 7, wantMsg: `unknown descriptor "@fortnightly"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := cron.Parse(tt.expr)
+			var syntaxErr *cron.SyntaxError
+			if !errors.As(err, &syntaxErr) || !errors.Is(err, cron.ErrSyntax) {
+				t.Fatalf("Parse() error = %v, want a SyntaxError", err)
+			}
+			if syntaxErr.Offset != tt.wantOffset || syntaxErr.Msg != tt.wantMsg {
+				t.Errorf("Parse() error at %d %q, want at %d %q", syntaxErr.Offset, syntaxErr.Msg, tt.wantOffset, tt.wantMsg)
+			}
+		})
+	}
+
+	if _, err := cron.Parse("TZ=Mars/Olympus * * * * *"); !errors.Is(err, utime.ErrUnknownZone) {
+		t.Errorf("Parse() error = %v, want ErrUnknownZone", err)
+	}
+}
+
+func TestSchedule_NextN(t *testing.T) {
+	madrid := location(t, "Europe/Madrid")
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []time.Time
+	}{
+		{
+			name: "Weekdays",
+			expr: "0 9 * * MON-FRI",
+			from: time.Date(2024, time.July, 26, 10, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.July, 29, 9, 0, 0, 0, madrid),
+				time.Date(2024, time.July, 30, 9, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name: "Seconds",
+			expr: "*/15 * * * * *",
+			from: time.Date(2024, time.July, 26, 10, 0, 7, 500, madrid),
+			want: []time.Time{
+				time.Date(2024, time.July, 26, 10, 0, 15, 0, madrid),
+				time.Date(2024, time.July, 26, 10, 0, 30, 0, madrid),
+			},
+		},
+		{
+			name: "Day of month or day of week",
+			expr: "0 0 13 * FRI",
+			from: time.Date(2024, time.September, 1, 0, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.September, 6, 0, 0, 0, 0, madrid),
+				time.Date(2024, time.September, 13, 0, 0, 0, 0, madrid),
+				time.Date(2024, time.September, 20, 0, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name: "Day of month with a step and day of week",
+			expr: "0 0 */2 * FRI",
+			from: time.Date(2024, time.September, 1, 0, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.September, 13, 0, 0, 0, 0, madrid),
+				time.Date(2024, time.September, 27, 0, 0, 0, 0, madrid),
+				time.Date(2024, time.October, 11, 0, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name: "Sunday as 7",
+			expr: "0 12 * * 7",
+			from: time.Date(2024, time.September, 1, 13, 0, 0, 0, madrid),
+			want: []time.Time{time.Date(2024, time.September, 8, 12, 0, 0, 0, madrid)},
+		},
+		{
+			name: "Leap day",
+			expr: "0 0 29 2 *",
+			from: time.Date(2024, time.March, 1, 0, 0, 0, 0, madrid),
+			want: []time.Time{time.Date(2028, time.February, 29, 0, 0, 0, 0, madrid)},
+		},
+		{
+			name: "Never",
+			expr: "0 0 30 2 *",
+			from: time.Date(2024, time.March, 1, 0, 0, 0, 0, madrid),
+		},
+		{
+			name: "Skipped by the clocks going forward",
+			expr: "TZ=Europe/Madrid 30 2 * * *",
+			from: time.Date(2024, time.March, 30, 12, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.March, 31, 1, 0, 0, 0, time.UTC),
+				time.Date(2024, time.April, 1, 0, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Repeated by the clocks going back",
+			expr: "TZ=Europe/Madrid */30 * * * *",
+			from: time.Date(2024, time.October, 26, 23, 45, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, time.October, 27, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, time.October, 27, 0, 30, 0, 0, time.UTC),
+				time.Date(2024, time.October, 27, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Daily",
+			expr: "@daily",
+			from: time.Date(2024, time.October, 26, 12, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.October, 27, 0, 0, 0, 0, madrid),
+				time.Date(2024, time.October, 28, 0, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name: "Every",
+			expr: "@every 90m",
+			from: time.Date(2024, time.October, 26, 12, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.October, 26, 13, 30, 0, 0, madrid),
+				time.Date(2024, time.October, 26, 15, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name: "Every in upper case",
+			expr: "@EVERY 90m",
+			from: time.Date(2024, time.October, 26, 12, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.October, 26, 13, 30, 0, 0, madrid),
+				time.Date(2024, time.October, 26, 15, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name: "Location prefix",
+			expr: "CRON_TZ=Asia/Tokyo 0 9 * * *",
+			from: time.Date(2024, time.October, 26, 12, 0, 0, 0, time.UTC),
+			want: []time.Time{time.Date(2024, time.October, 27, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := cron.MustParse(tt.expr)
+			got := s.NextN(tt.from, len(tt.want)+1)
+			if len(tt.want) > 0 {
+				got = got[:len(tt.want)]
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("NextN() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("NextN()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+
+			// Prev walks the same fire times back
+			if len(tt.want) > 1 {
+				last := tt.want[len(tt.want)-1]
+				prev := s.PrevN(last, len(tt.want)-1)
+				for i := range prev {
+					if want := tt.want[len(tt.want)-2-i]; !prev[i].Equal(want) {
+						t.Errorf("PrevN()[%d] = %v, want %v", i, prev[i], want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSchedule_Prev(t *testing.T) {
+	s := cron.MustParse("*/30 * * * *").In(location(t, "Europe/Madrid"))
+
+	// 02:40 of the repeated hour, after 02:30 fired in the first one
+	got := s.Prev(time.Date(2024, time.October, 27, 1, 40, 0, 0, time.UTC))
+	if want := time.Date(2024, time.October, 27, 0, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Prev() = %v, want %v", got, want)
+	}
+
+	if got := cron.MustParse("0 0 30 2 *").Prev(time.Now()); !got.IsZero() {
+		t.Errorf("Prev() = %v, want the zero time", got)
+	}
+}
+
+func TestSchedule_In(t *testing.T) {
+	newYork := location(t, "America/New_York")
+	s := cron.MustParse("0 9 * * *")
+	in := s.In(newYork)
+
+	from := time.Date(2024, time.July, 26, 12, 0, 0, 0, time.UTC)
+	if got, want := in.Next(from), time.Date(2024, time.July, 26, 13, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+	if got, want := s.Next(from), time.Date(2024, time.July, 27, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+	if in.Location() != newYork || s.Location() != nil || in.String() != "0 9 * * *" {
+		t.Errorf("In() = %v in %v", in, in.Location())
+	}
+	if got := cron.MustParse("@every 1h").PrevN(from, 2); !reflect.DeepEqual(got, []time.Time{from.Add(-time.Hour), from.Add(-2 * time.Hour)}) {
+		t.Errorf("PrevN() = %v", got)
+	}
+}
+
+func BenchmarkSchedule_Next(b *testing.B) {
+	s := cron.MustParse("0 9 * * MON-FRI")
+	from := time.Date(2024, time.July, 26, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < b.N; i++ {
+		s.Next(from)
+	}
+}
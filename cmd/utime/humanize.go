@@ -0,0 +1,254 @@
+package utime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rounding is how Humanize and FormatDuration round the last unit they write.
+type Rounding int
+
+const (
+	RoundNearest Rounding = iota
+	RoundDown
+	RoundUp
+)
+
+// DurationStyle is how Humanize and FormatDuration write the units.
+type DurationStyle int
+
+const (
+	// StyleLong writes the names of the units, like 1 hour and 20 minutes.
+	StyleLong DurationStyle = iota
+	// StyleCompact writes their symbols, like 1h 20m.
+	StyleCompact
+)
+
+// The lengths of the units, with months of 30 days and years of 365.
+var unitLengths = map[Unit]time.Duration{
+	UnitSecond: time.Second,
+	UnitMinute: time.Minute,
+	UnitHour:   time.Hour,
+	UnitDay:    24 * time.Hour,
+	UnitWeek:   7 * 24 * time.Hour,
+	UnitMonth:  30 * 24 * time.Hour,
+	UnitYear:   365 * 24 * time.Hour,
+}
+
+var (
+	humanizeUnits = []Unit{UnitYear, UnitMonth, UnitWeek, UnitDay, UnitHour, UnitMinute, UnitSecond}
+	durationUnits = []Unit{UnitDay, UnitHour, UnitMinute, UnitSecond}
+)
+
+// FormatOption configures Humanize and FormatDuration.
+type FormatOption func(*formatOptions)
+
+type formatOptions struct {
+	clock     Clock
+	language  *Language
+	rounding  Rounding
+	precision int
+	style     DurationStyle
+}
+
+// WithClock sets the clock whose time Humanize is relative to, RealClock by default.
+func WithClock(clock Clock) FormatOption {
+	return func(o *formatOptions) {
+		o.clock = clock
+	}
+}
+
+// WithLanguage sets the language of the words, English by default.
+func WithLanguage(language *Language) FormatOption {
+	return func(o *formatOptions) {
+		o.language = language
+	}
+}
+
+// WithRounding sets the rounding of the last unit, RoundNearest by default.
+func WithRounding(rounding Rounding) FormatOption {
+	return func(o *formatOptions) {
+		o.rounding = rounding
+	}
+}
+
+// WithPrecision sets how many consecutive units are written from the largest one, 1 by default
+// for Humanize and 2 for FormatDuration. Zero writes all the units down to seconds.
+func WithPrecision(precision int) FormatOption {
+	return func(o *formatOptions) {
+		o.precision = precision
+	}
+}
+
+// WithStyle sets the style of the units, StyleLong by default for Humanize and StyleCompact
+// for FormatDuration.
+func WithStyle(style DurationStyle) FormatOption {
+	return func(o *formatOptions) {
+		o.style = style
+	}
+}
+
+// Humanize return the time from now to t in words, like "3 hours ago" or "in 2 days", in
+// years, months, weeks, days, hours, minutes and seconds. Beyond the about 292 years of a
+// time.Duration, the years and months are counted on the calendar and the smaller units left out.
+func Humanize(t time.Time, options ...FormatOption) string {
+	o := formatOptions{clock: RealClock, language: English, precision: 1, style: StyleLong}
+	for _, option := range options {
+		option(&o)
+	}
+
+	now := o.clock.Now()
+	d := t.Sub(now)
+	var parts []unitAmount
+	switch d {
+	case math.MaxInt64:
+		parts = calendarParts(now, t, o)
+	case math.MinInt64:
+		parts = calendarParts(t, now, o)
+	default:
+		parts = splitDuration(d.Abs(), humanizeUnits, o)
+	}
+	if len(parts) == 0 {
+		return o.language.JustNow
+	}
+
+	format := o.language.FutureFormat
+	if d < 0 {
+		format = o.language.PastFormat
+	}
+	return fmt.Sprintf(format, o.language.formatUnits(parts, o.style))
+}
+
+// FormatDuration return d in days, hours, minutes and seconds, like "1h 20m", with a minus
+// sign when negative.
+func FormatDuration(d time.Duration, options ...FormatOption) string {
+	o := formatOptions{language: English, precision: 2, style: StyleCompact}
+	for _, option := range options {
+		option(&o)
+	}
+
+	parts := splitDuration(d.Abs(), durationUnits, o)
+	if len(parts) == 0 {
+		return o.language.formatUnits([]unitAmount{{unit: UnitSecond}}, o.style)
+	}
+
+	text := o.language.formatUnits(parts, o.style)
+	if d < 0 {
+		text = "-" + text
+	}
+	return text
+}
+
+type unitAmount struct {
+	unit Unit
+	n    int64
+}
+
+// splitDuration return the amounts of the units of d, rounded to the last unit of the
+// precision, without the zero ones.
+func splitDuration(d time.Duration, units []Unit, o formatOptions) []unitAmount {
+	first, last := leadingUnit(d, units), 0
+	for {
+		last = len(units) - 1
+		if o.precision > 0 {
+			last = min(first+o.precision-1, last)
+		}
+		d = roundDuration(d, unitLengths[units[last]], o.rounding)
+
+		// rounding up can reach a larger unit, like 59m40s to 1h
+		if lead := leadingUnit(d, units); lead < first {
+			first = lead
+			continue
+		}
+		break
+	}
+
+	var parts []unitAmount
+	for _, unit := range units[first : last+1] {
+		length := unitLengths[unit]
+		if n := d / length; n > 0 {
+			parts = append(parts, unitAmount{unit: unit, n: int64(n)})
+		}
+		d %= length
+	}
+	return parts
+}
+
+// calendarParts return the years and months from from to to, too far apart for a duration,
+// rounded to years with a precision of 1 and to months otherwise.
+func calendarParts(from, to time.Time, o formatOptions) []unitAmount {
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	if from.AddDate(0, months, 0).After(to) {
+		months--
+	}
+
+	step := 1
+	if o.precision == 1 {
+		step = 12
+	}
+	whole := months / step * step
+	rest := time.Duration(months-whole)*unitLengths[UnitMonth] + to.Sub(from.AddDate(0, months, 0))
+	unit := time.Duration(step) * unitLengths[UnitMonth]
+	if o.rounding == RoundUp && rest > 0 || o.rounding == RoundNearest && rest >= unit-rest {
+		whole += step
+	}
+
+	var parts []unitAmount
+	if whole >= 12 {
+		parts = append(parts, unitAmount{unit: UnitYear, n: int64(whole / 12)})
+	}
+	if whole%12 > 0 {
+		parts = append(parts, unitAmount{unit: UnitMonth, n: int64(whole % 12)})
+	}
+	return parts
+}
+
+// leadingUnit return the index of the largest unit not longer than d, or the smallest unit.
+func leadingUnit(d time.Duration, units []Unit) int {
+	for i, unit := range units {
+		if d >= unitLengths[unit] {
+			return i
+		}
+	}
+	return len(units) - 1
+}
+
+// roundDuration return d rounded to a multiple of unit, saturating at the largest multiple below
+// the maximum duration.
+func roundDuration(d, unit time.Duration, rounding Rounding) time.Duration {
+	rest := d % unit
+	d -= rest
+	if d > math.MaxInt64-unit {
+		return d
+	}
+	if rounding == RoundUp && rest > 0 || rounding == RoundNearest && rest >= unit-rest {
+		d += unit
+	}
+	return d
+}
+
+// formatUnits writes the amounts with the names or the symbols of the units.
+func (l *Language) formatUnits(parts []unitAmount, style DurationStyle) string {
+	words := make([]string, len(parts))
+	for i, part := range parts {
+		n := strconv.FormatInt(part.n, 10)
+		if style == StyleCompact {
+			words[i] = n + l.UnitSymbols[part.unit]
+			continue
+		}
+		names := l.UnitNames[part.unit]
+		if part.n == 1 {
+			words[i] = n + " " + names[0]
+		} else {
+			words[i] = n + " " + names[1]
+		}
+	}
+
+	if style == StyleCompact || len(words) == 1 {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + l.And + words[len(words)-1]
+}
@@ -0,0 +1,117 @@
+package utime_test
+
+import (
+	"golibs/cmd/utime"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHumanize(t *testing.T) {
+	clock := utime.NewFakeClock(epoch)
+
+	tests := []struct {
+		name    string
+		d       time.Duration
+		options []utime.FormatOption
+		want    string
+	}{
+		{name: "Past", d: -3 * time.Hour, want: "3 hours ago"},
+		{name: "Future", d: 48 * time.Hour, want: "in 2 days"},
+		{name: "Singular", d: time.Hour, want: "in 1 hour"},
+		{name: "Weeks", d: -15 * 24 * time.Hour, want: "2 weeks ago"},
+		{name: "Years", d: 800 * 24 * time.Hour, want: "in 2 years"},
+		{name: "Now", d: 400 * time.Millisecond, want: "just now"},
+		{name: "Round nearest", d: -89 * time.Minute, want: "1 hour ago"},
+		{name: "Round up", d: -89 * time.Minute, options: []utime.FormatOption{utime.WithRounding(utime.RoundUp)}, want: "2 hours ago"},
+		{name: "Round down", d: -119 * time.Minute, options: []utime.FormatOption{utime.WithRounding(utime.RoundDown)}, want: "1 hour ago"},
+		{name: "Carry", d: 59*time.Minute + 40*time.Second, want: "in 1 hour"},
+		{name: "Precision", d: -90 * time.Minute, options: []utime.FormatOption{utime.WithPrecision(2)}, want: "1 hour and 30 minutes ago"},
+		{name: "Zero units", d: -(time.Hour + 20*time.Second), options: []utime.FormatOption{utime.WithPrecision(2)}, want: "1 hour ago"},
+		{name: "Compact", d: 3 * time.Hour, options: []utime.FormatOption{utime.WithStyle(utime.StyleCompact)}, want: "in 3h"},
+		{name: "Spanish past", d: -5 * time.Minute, options: []utime.FormatOption{utime.WithLanguage(utime.Spanish)}, want: "hace 5 minutos"},
+		{name: "Spanish future", d: 24 * time.Hour, options: []utime.FormatOption{utime.WithLanguage(utime.Spanish)}, want: "dentro de 1 día"},
+		{name: "Spanish now", options: []utime.FormatOption{utime.WithLanguage(utime.Spanish)}, want: "ahora mismo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]utime.FormatOption{utime.WithClock(clock)}, tt.options...)
+			if got := utime.Humanize(epoch.Add(tt.d), options...); got != tt.want {
+				t.Errorf("Humanize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// the times beyond the range of a duration are counted on the calendar
+	extremes := []struct {
+		t       time.Time
+		options []utime.FormatOption
+		want    string
+	}{
+		{t: time.Time{}, want: "2024 years ago"},
+		{t: time.Time{}, options: []utime.FormatOption{utime.WithRounding(utime.RoundDown)}, want: "2023 years ago"},
+		{t: time.Time{}, options: []utime.FormatOption{utime.WithRounding(utime.RoundUp)}, want: "2024 years ago"},
+		{t: time.Time{}, options: []utime.FormatOption{utime.WithPrecision(2)}, want: "2023 years and 7 months ago"},
+		{t: time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC), want: "in 7975 years"},
+		{t: time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC), options: []utime.FormatOption{utime.WithRounding(utime.RoundUp)}, want: "in 7976 years"},
+	}
+	for _, tt := range extremes {
+		options := append([]utime.FormatOption{utime.WithClock(clock)}, tt.options...)
+		if got := utime.Humanize(tt.t, options...); got != tt.want {
+			t.Errorf("Humanize(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+
+	// the same time drifts into the past as the clock advances
+	meeting := epoch.Add(90 * time.Second)
+	clock.Advance(5 * time.Minute)
+	if got := utime.Humanize(meeting, utime.WithClock(clock)); got != "4 minutes ago" {
+		t.Errorf("Humanize() after Advance = %q, want %q", got, "4 minutes ago")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       time.Duration
+		options []utime.FormatOption
+		want    string
+	}{
+		{name: "Compact", d: 80 * time.Minute, want: "1h 20m"},
+		{name: "Rounded", d: 80*time.Minute + 30*time.Second, want: "1h 21m"},
+		{name: "Round down", d: 80*time.Minute + 30*time.Second, options: []utime.FormatOption{utime.WithRounding(utime.RoundDown)}, want: "1h 20m"},
+		{name: "Full precision", d: 80*time.Minute + 30*time.Second, options: []utime.FormatOption{utime.WithPrecision(0)}, want: "1h 20m 30s"},
+		{name: "Days", d: 26 * time.Hour, want: "1d 2h"},
+		{name: "Zero units", d: time.Hour + 20*time.Second, want: "1h"},
+		{name: "Seconds", d: 1500 * time.Millisecond, want: "2s"},
+		{name: "Zero", want: "0s"},
+		{name: "Negative", d: -90 * time.Second, want: "-1m 30s"},
+		{name: "Maximum", d: math.MaxInt64, options: []utime.FormatOption{utime.WithRounding(utime.RoundUp)}, want: "106751d 23h"},
+		{name: "Minimum", d: math.MinInt64, options: []utime.FormatOption{utime.WithRounding(utime.RoundUp)}, want: "-106751d 23h"},
+		{
+			name:    "Long",
+			d:       26*time.Hour + 3*time.Minute,
+			options: []utime.FormatOption{utime.WithStyle(utime.StyleLong), utime.WithPrecision(3)},
+			want:    "1 day, 2 hours and 3 minutes",
+		},
+		{
+			name:    "Spanish",
+			d:       90 * time.Minute,
+			options: []utime.FormatOption{utime.WithStyle(utime.StyleLong), utime.WithLanguage(utime.Spanish)},
+			want:    "1 hora y 30 minutos",
+		},
+		{
+			name:    "Spanish compact",
+			d:       90 * time.Minute,
+			options: []utime.FormatOption{utime.WithLanguage(utime.Spanish)},
+			want:    "1h 30min",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := utime.FormatDuration(tt.d, tt.options...); got != tt.want {
+				t.Errorf("FormatDuration() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
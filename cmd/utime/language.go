@@ -0,0 +1,186 @@
+package utime
+
+import "time"
+
+// Unit is a clock or calendar unit of the relative dates and durations.
+type Unit int
+
+const (
+	UnitSecond Unit = iota
+	UnitMinute
+	UnitHour
+	UnitDay
+	UnitWeek
+	UnitMonth
+	UnitYear
+)
+
+// Language is a language pack of ParseRelative, Humanize and FormatDuration. The words read by
+// ParseRelative are lowercase and without accents, and can be phrases of several words, like
+// "pasado manana"; the longest phrase is matched first. Days are offsets from today and the
+// words of Next, Last and This modify a weekday or a unit, before or after it.
+type Language struct {
+	Name string
+
+	Now      []string
+	Days     map[string]int
+	Weekdays map[string]time.Weekday
+	Units    map[string]Unit
+	Numbers  map[string]int
+
+	Next []string
+	Last []string
+	This []string
+
+	// Future and Past go before an amount, like in 2 days, FutureSuffix and PastSuffix
+	// after it, like 2 days ago.
+	Future       []string
+	Past         []string
+	FutureSuffix []string
+	PastSuffix   []string
+
+	At       []string
+	AM       []string
+	PM       []string
+	Noon     []string
+	Midnight []string
+
+	// Ignore are the words without meaning, like articles.
+	Ignore []string
+
+	// UnitNames are the singular and plural names of the units written by Humanize and
+	// FormatDuration, and UnitSymbols their compact forms.
+	UnitNames   map[Unit][2]string
+	UnitSymbols map[Unit]string
+	// FutureFormat and PastFormat place a duration written by Humanize, like "in %s".
+	FutureFormat string
+	PastFormat   string
+	// JustNow is written by Humanize for a duration rounded to zero, and And before the last
+	// of several units.
+	JustNow string
+	And     string
+}
+
+// English is the language pack of English.
+var English = &Language{
+	Name: "english",
+	Now:  []string{"now", "right now"},
+	Days: map[string]int{
+		"today": 0, "tomorrow": 1, "yesterday": -1,
+		"day after tomorrow": 2, "day before yesterday": -2,
+	},
+	Weekdays: map[string]time.Weekday{
+		"sunday": time.Sunday, "sun": time.Sunday,
+		"monday": time.Monday, "mon": time.Monday,
+		"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+		"wednesday": time.Wednesday, "wed": time.Wednesday,
+		"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+		"friday": time.Friday, "fri": time.Friday,
+		"saturday": time.Saturday, "sat": time.Saturday,
+	},
+	Units: map[string]Unit{
+		"second": UnitSecond, "seconds": UnitSecond, "sec": UnitSecond, "secs": UnitSecond,
+		"minute": UnitMinute, "minutes": UnitMinute, "min": UnitMinute, "mins": UnitMinute,
+		"hour": UnitHour, "hours": UnitHour, "hr": UnitHour, "hrs": UnitHour,
+		"day": UnitDay, "days": UnitDay,
+		"week": UnitWeek, "weeks": UnitWeek,
+		"month": UnitMonth, "months": UnitMonth,
+		"year": UnitYear, "years": UnitYear,
+	},
+	Numbers: map[string]int{
+		"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+		"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+		"a couple of": 2, "a few": 3,
+	},
+	Next:         []string{"next", "coming"},
+	Last:         []string{"last", "previous", "past"},
+	This:         []string{"this"},
+	Future:       []string{"in", "within"},
+	FutureSuffix: []string{"from now", "later"},
+	PastSuffix:   []string{"ago", "earlier"},
+	At:           []string{"at"},
+	AM:           []string{"am", "in the morning"},
+	PM:           []string{"pm", "in the afternoon", "in the evening", "at night"},
+	Noon:         []string{"noon", "midday"},
+	Midnight:     []string{"midnight"},
+	Ignore:       []string{"the", "on"},
+	UnitNames: map[Unit][2]string{
+		UnitSecond: {"second", "seconds"},
+		UnitMinute: {"minute", "minutes"},
+		UnitHour:   {"hour", "hours"},
+		UnitDay:    {"day", "days"},
+		UnitWeek:   {"week", "weeks"},
+		UnitMonth:  {"month", "months"},
+		UnitYear:   {"year", "years"},
+	},
+	UnitSymbols: map[Unit]string{
+		UnitSecond: "s", UnitMinute: "m", UnitHour: "h", UnitDay: "d",
+		UnitWeek: "w", UnitMonth: "mo", UnitYear: "y",
+	},
+	FutureFormat: "in %s",
+	PastFormat:   "%s ago",
+	JustNow:      "just now",
+	And:          " and ",
+}
+
+// Spanish is the language pack of Spanish.
+var Spanish = &Language{
+	Name: "spanish",
+	Now:  []string{"ahora", "ahora mismo"},
+	Days: map[string]int{
+		"hoy": 0, "manana": 1, "ayer": -1,
+		"pasado manana": 2, "anteayer": -2, "antes de ayer": -2, "antier": -2,
+	},
+	Weekdays: map[string]time.Weekday{
+		"domingo": time.Sunday, "dom": time.Sunday,
+		"lunes": time.Monday, "lun": time.Monday,
+		"martes": time.Tuesday, "mar": time.Tuesday,
+		"miercoles": time.Wednesday, "mie": time.Wednesday,
+		"jueves": time.Thursday, "jue": time.Thursday,
+		"viernes": time.Friday, "vie": time.Friday,
+		"sabado": time.Saturday, "sab": time.Saturday,
+	},
+	Units: map[string]Unit{
+		"segundo": UnitSecond, "segundos": UnitSecond, "seg": UnitSecond,
+		"minuto": UnitMinute, "minutos": UnitMinute, "min": UnitMinute,
+		"hora": UnitHour, "horas": UnitHour,
+		"dia": UnitDay, "dias": UnitDay,
+		"semana": UnitWeek, "semanas": UnitWeek,
+		"mes": UnitMonth, "meses": UnitMonth,
+		"ano": UnitYear, "anos": UnitYear,
+	},
+	Numbers: map[string]int{
+		"un": 1, "una": 1, "uno": 1, "dos": 2, "tres": 3, "cuatro": 4, "cinco": 5, "seis": 6,
+		"siete": 7, "ocho": 8, "nueve": 9, "diez": 10, "once": 11, "doce": 12,
+		"un par de": 2, "unos pocos": 3, "unas pocas": 3,
+	},
+	Next:       []string{"proximo", "proxima", "que viene", "siguiente"},
+	Last:       []string{"pasado", "pasada", "ultimo", "ultima", "anterior"},
+	This:       []string{"este", "esta"},
+	Future:     []string{"en", "dentro de"},
+	Past:       []string{"hace"},
+	PastSuffix: []string{"atras"},
+	At:         []string{"a las", "a la", "a"},
+	AM:         []string{"am", "de la manana", "por la manana", "de la madrugada"},
+	PM:         []string{"pm", "de la tarde", "de la noche", "por la tarde", "por la noche"},
+	Noon:       []string{"mediodia"},
+	Midnight:   []string{"medianoche"},
+	Ignore:     []string{"el", "la", "los", "las", "de"},
+	UnitNames: map[Unit][2]string{
+		UnitSecond: {"segundo", "segundos"},
+		UnitMinute: {"minuto", "minutos"},
+		UnitHour:   {"hora", "horas"},
+		UnitDay:    {"día", "días"},
+		UnitWeek:   {"semana", "semanas"},
+		UnitMonth:  {"mes", "meses"},
+		UnitYear:   {"año", "años"},
+	},
+	UnitSymbols: map[Unit]string{
+		UnitSecond: "s", UnitMinute: "min", UnitHour: "h", UnitDay: "d",
+		UnitWeek: "sem", UnitMonth: "mes", UnitYear: "a",
+	},
+	FutureFormat: "dentro de %s",
+	PastFormat:   "hace %s",
+	JustNow:      "ahora mismo",
+	And:          " y ",
+}
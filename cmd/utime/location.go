@@ -0,0 +1,119 @@
+package utime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Errors returned when a local time falls in a daylight saving transition.
+var (
+	ErrAmbiguousTime   = errors.New("time: ambiguous local time")
+	ErrNonExistentTime = errors.New("time: non-existent local time")
+)
+
+// DSTPolicy chooses the time of a local time that happens twice, when the clocks go back, or
+// never, when they go forward.
+type DSTPolicy int
+
+const (
+	// DSTEarliest takes the first occurrence of an ambiguous time.
+	DSTEarliest DSTPolicy = iota
+	// DSTLatest takes the second occurrence of an ambiguous time.
+	DSTLatest
+	// DSTError returns ErrAmbiguousTime or ErrNonExistentTime.
+	DSTError
+)
+
+// ParseInLocation parse date with layout in the named location. Dates with a time zone or
+// offset keep it. Otherwise the local time is resolved with policy when it is ambiguous and,
+// unless the policy is DSTError, non-existent times are moved forward by the length of the gap.
+func ParseInLocation(layout, date, location string, policy DSTPolicy) (time.Time, error) {
+	loc, err := LoadLocation(location)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(layout, date)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return resolve(layout, date, t, loc, policy)
+}
+
+// ParseStringToISODateInLocation parse string to date in the named location. Format used is 2006-01-02 15:04:05.
+func ParseStringToISODateInLocation(date, location string, policy DSTPolicy) (time.Time, error) {
+	return ParseInLocation(FormatISODate, date, location, policy)
+}
+
+// ParseAnyInLocation is ParseAny for dates in the named location, resolving the local times
+// like ParseInLocation. A WithLocation option is ignored.
+func ParseAnyInLocation(date, location string, policy DSTPolicy, options ...Option) (time.Time, string, error) {
+	loc, err := LoadLocation(location)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	t, layout, err := ParseAny(date, append(options[:len(options):len(options)], WithLocation(time.UTC))...)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	if layout == LayoutUnix {
+		return t.In(loc), layout, nil
+	}
+	t, err = resolve(layout, strings.TrimSpace(date), t, loc, policy)
+	return t, layout, err
+}
+
+// resolve return t, parsed as UTC, in loc. When date has no time zone its wall clock is
+// taken as a local time in loc.
+func resolve(layout, date string, t time.Time, loc *time.Location, policy DSTPolicy) (time.Time, error) {
+	// a date with its own offset parses to the same instant whatever the default location,
+	// and the literal Z of FormatRFC3339 stands for UTC
+	if layout == FormatRFC3339 {
+		return t.In(loc), nil
+	}
+	if other, err := time.ParseInLocation(layout, date, time.FixedZone("", 3600)); err == nil && other.Equal(t) {
+		return t.In(loc), nil
+	}
+
+	return localTime(t, loc, policy)
+}
+
+// localTime return the time in loc whose wall clock is the one of wall, a UTC time.
+func localTime(wall time.Time, loc *time.Location, policy DSTPolicy) (time.Time, error) {
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var candidates []time.Time
+	for _, offset := range []int{before, after} {
+		t := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if _, actual := t.Zone(); actual == offset && (len(candidates) == 0 || !candidates[0].Equal(t)) {
+			candidates = append(candidates, t)
+		}
+	}
+
+	switch {
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case policy == DSTError && len(candidates) == 0:
+		return time.Time{}, fmt.Errorf("%w: %s in %s", ErrNonExistentTime, wall.Format(FormatISODate), loc)
+	case policy == DSTError:
+		return time.Time{}, fmt.Errorf("%w: %s in %s", ErrAmbiguousTime, wall.Format(FormatISODate), loc)
+	case len(candidates) == 0:
+		// the offset before the gap moves the wall clock forward by its length
+		return wall.Add(-time.Duration(before) * time.Second).In(loc), nil
+	}
+
+	earliest, latest := candidates[0], candidates[1]
+	if latest.Before(earliest) {
+		earliest, latest = latest, earliest
+	}
+	if policy == DSTLatest {
+		return latest, nil
+	}
+	return earliest, nil
+}
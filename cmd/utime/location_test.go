@@ -0,0 +1,114 @@
+package utime_test
+
+import (
+	"errors"
+	"golibs/cmd/utime"
+	"testing"
+	"time"
+)
+
+func TestParseInLocation(t *testing.T) {
+	type args struct {
+		layout   string
+		date     string
+		location string
+		policy   utime.DSTPolicy
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name: "Local time in Madrid",
+			args: args{layout: utime.FormatISODate, date: "2024-07-23 10:00:00", location: "Europe/Madrid"},
+			want: time.Date(2024, time.July, 23, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Offset is kept",
+			args: args{layout: utime.FormatISO8601, date: "2018-03-20T12:49:10.000-03:00", location: "Europe/Madrid"},
+			want: time.Date(2018, time.March, 20, 15, 49, 10, 0, time.UTC),
+		},
+		{
+			name: "RFC3339 is UTC",
+			args: args{layout: utime.FormatRFC3339, date: "2018-03-20T12:49:10Z", location: "Europe/Madrid"},
+			want: time.Date(2018, time.March, 20, 12, 49, 10, 0, time.UTC),
+		},
+		{
+			name: "Ambiguous earliest",
+			args: args{layout: utime.FormatISODate, date: "2024-10-27 02:30:00", location: "Europe/Madrid", policy: utime.DSTEarliest},
+			want: time.Date(2024, time.October, 27, 0, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "Ambiguous latest",
+			args: args{layout: utime.FormatISODate, date: "2024-10-27 02:30:00", location: "Europe/Madrid", policy: utime.DSTLatest},
+			want: time.Date(2024, time.October, 27, 1, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "Ambiguous error",
+			args:    args{layout: utime.FormatISODate, date: "2024-10-27 02:30:00", location: "Europe/Madrid", policy: utime.DSTError},
+			wantErr: utime.ErrAmbiguousTime,
+		},
+		{
+			name: "Non-existent moves forward",
+			args: args{layout: utime.FormatISODate, date: "2024-03-31 02:30:00", location: "Europe/Madrid", policy: utime.DSTLatest},
+			want: time.Date(2024, time.March, 31, 1, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "Non-existent error",
+			args:    args{layout: utime.FormatISODate, date: "2024-03-31 02:30:00", location: "Europe/Madrid", policy: utime.DSTError},
+			wantErr: utime.ErrNonExistentTime,
+		},
+		{
+			name: "Ambiguous in the southern hemisphere",
+			args: args{layout: utime.FormatISODate, date: "2024-04-07 02:30:00", location: "Australia/Sydney", policy: utime.DSTLatest},
+			want: time.Date(2024, time.April, 6, 16, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "Invalid location",
+			args:    args{layout: utime.FormatISODate, date: "2024-07-23 10:00:00", location: "America/Argentina/Buenos_Air"},
+			wantErr: utime.ErrLocation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := utime.ParseInLocation(tt.args.layout, tt.args.date, tt.args.location, tt.args.policy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseInLocation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseInLocation() got = %v, want %v", got, tt.want)
+			}
+			if err == nil && got.Location().String() != tt.args.location {
+				t.Errorf("ParseInLocation() location = %v, want %v", got.Location(), tt.args.location)
+			}
+		})
+	}
+}
+
+func TestParseAnyInLocation(t *testing.T) {
+	got, layout, err := utime.ParseAnyInLocation("27/10/2024 02:30", "Europe/Madrid", utime.DSTLatest)
+	if err != nil {
+		t.Fatalf("ParseAnyInLocation() error = %v", err)
+	}
+	if want := time.Date(2024, time.October, 27, 1, 30, 0, 0, time.UTC); !got.Equal(want) || layout != "2/1/2006 15:04" {
+		t.Errorf("ParseAnyInLocation() = %v, %q, want %v", got, layout, want)
+	}
+
+	got, _, err = utime.ParseAnyInLocation("1721728800", "Europe/Madrid", utime.DSTError)
+	if err != nil || got.Hour() != 12 {
+		t.Errorf("ParseAnyInLocation() = %v, %v, want 12:00 in Madrid", got, err)
+	}
+}
+
+func TestParseStringToISODateInLocation(t *testing.T) {
+	got, err := utime.ParseStringToISODateInLocation("2018-03-20 12:49:10", "America/Argentina/Buenos_Aires", utime.DSTError)
+	if err != nil {
+		t.Fatalf("ParseStringToISODateInLocation() error = %v", err)
+	}
+	if want := time.Date(2018, time.March, 20, 15, 49, 10, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("ParseStringToISODateInLocation() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,184 @@
+package utime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownLayout is returned by ParseAny when the date matches none of the layouts.
+var ErrUnknownLayout = errors.New("time: date does not match any layout")
+
+// LayoutUnix is the layout reported by ParseAny for dates given as seconds since the epoch,
+// with an optional fraction. It can be used in WithLayouts like any other layout.
+const LayoutUnix = "unix"
+
+// minUnixDigits is the number of digits of the seconds since the epoch read by the default
+// layouts, from 1973, so years and other short numbers are not taken as times in 1970.
+const minUnixDigits = 9
+
+// Layouts tried by ParseAny before and after the numeric ones, whose order depends on the
+// day first preference.
+var (
+	leadingLayouts = []string{
+		FormatISO8601,
+		FormatRFC3339,
+		time.RFC3339Nano,
+		FormatISODate,
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+		FileFormat,
+	}
+	dayFirstLayouts = []string{
+		"2/1/2006 15:04:05",
+		"2/1/2006 15:04",
+		"2/1/2006",
+		"2-1-2006",
+		"2.1.2006",
+	}
+	monthFirstLayouts = []string{
+		"1/2/2006 15:04:05",
+		"1/2/2006 15:04",
+		"1/2/2006",
+		"1-2-2006",
+		"1.2.2006",
+	}
+	trailingLayouts = []string{
+		"Jan 2 2006",
+		"Jan 2, 2006",
+		"2 Jan 2006",
+		"January 2 2006",
+		"January 2, 2006",
+		"2 January 2006",
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC850,
+		time.ANSIC,
+		LayoutUnix,
+	}
+)
+
+// Option configures ParseAny.
+type Option func(*parseOptions)
+
+type parseOptions struct {
+	layouts  []string
+	extra    []string
+	dayFirst bool
+	location *time.Location
+}
+
+// WithLayouts replaces the layouts tried by ParseAny, in order.
+func WithLayouts(layouts ...string) Option {
+	return func(o *parseOptions) {
+		o.layouts = layouts
+	}
+}
+
+// WithExtraLayouts adds layouts to try after the default ones, or the ones of WithLayouts.
+func WithExtraLayouts(layouts ...string) Option {
+	return func(o *parseOptions) {
+		o.extra = append(o.extra, layouts...)
+	}
+}
+
+// WithDayFirst sets whether ambiguous numeric dates like 03/07/2024 are read day first, the
+// default, or month first. The other order is still tried when the preferred one fails.
+func WithDayFirst(dayFirst bool) Option {
+	return func(o *parseOptions) {
+		o.dayFirst = dayFirst
+	}
+}
+
+// WithLocation sets the location of the dates without a time zone, UTC by default.
+func WithLocation(location *time.Location) Option {
+	return func(o *parseOptions) {
+		o.location = location
+	}
+}
+
+// ParseAny parse date trying in order the four layouts of this package, RFC 3339 with offset,
+// numeric dates in both day and month first order, dates with month names, the RFC 1123
+// layouts and finally seconds since the epoch, with at least 9 digits. It return the time and
+// the layout that matched.
+func ParseAny(date string, options ...Option) (time.Time, string, error) {
+	o := parseOptions{dayFirst: true, location: time.UTC}
+	for _, option := range options {
+		option(&o)
+	}
+
+	layouts, defaults := o.layouts, 0
+	if layouts == nil {
+		layouts = defaultLayouts(o.dayFirst)
+		defaults = len(layouts)
+	}
+
+	date = strings.TrimSpace(date)
+	for i, layout := range append(layouts[:len(layouts):len(layouts)], o.extra...) {
+		if layout == LayoutUnix {
+			if t, ok := parseUnix(date); ok && (i >= defaults || unixDigits(date) >= minUnixDigits) {
+				return t.In(o.location), layout, nil
+			}
+			continue
+		}
+		// the literal Z of FormatRFC3339 stands for UTC, not for the default location
+		if layout == FormatRFC3339 {
+			if t, err := time.Parse(layout, date); err == nil {
+				return t.In(o.location), layout, nil
+			}
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, date, o.location); err == nil {
+			return t, layout, nil
+		}
+	}
+
+	return time.Time{}, "", fmt.Errorf("%w: %q", ErrUnknownLayout, date)
+}
+
+func defaultLayouts(dayFirst bool) []string {
+	first, second := dayFirstLayouts, monthFirstLayouts
+	if !dayFirst {
+		first, second = second, first
+	}
+
+	layouts := make([]string, 0, len(leadingLayouts)+len(first)+len(second)+len(trailingLayouts))
+	layouts = append(layouts, leadingLayouts...)
+	layouts = append(layouts, first...)
+	layouts = append(layouts, second...)
+	return append(layouts, trailingLayouts...)
+}
+
+// unixDigits return the number of digits of the whole seconds of date.
+func unixDigits(date string) int {
+	whole, _, _ := strings.Cut(date, ".")
+	return len(strings.TrimPrefix(whole, "-"))
+}
+
+// parseUnix parse seconds since the epoch, with an optional sign and fraction.
+func parseUnix(date string) (time.Time, bool) {
+	whole, fraction, _ := strings.Cut(date, ".")
+	if len(fraction) > 9 {
+		fraction = fraction[:9]
+	}
+
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || strings.HasPrefix(whole, "+") {
+		return time.Time{}, false
+	}
+
+	var nanos int64
+	if fraction != "" {
+		nanos, err = strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+		if err != nil || nanos < 0 || strings.HasPrefix(fraction, "+") {
+			return time.Time{}, false
+		}
+		if strings.HasPrefix(whole, "-") {
+			nanos = -nanos
+		}
+	}
+
+	return time.Unix(seconds, nanos).UTC(), true
+}
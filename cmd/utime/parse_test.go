@@ -0,0 +1,154 @@
+package utime_test
+
+import (
+	"errors"
+	"golibs/cmd/utime"
+	"testing"
+	"time"
+)
+
+func TestParseAny(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		date    string
+		options []utime.Option
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       time.Time
+		wantLayout string
+		wantErr    error
+	}{
+		{
+			name:       "ISO8601",
+			args:       args{date: "2018-03-20T12:49:10.000-03:00"},
+			want:       time.Date(2018, time.March, 20, 15, 49, 10, 0, time.UTC),
+			wantLayout: utime.FormatISO8601,
+		},
+		{
+			name:       "RFC3339 with offset",
+			args:       args{date: "2024-07-23T10:00:00+02:00"},
+			want:       time.Date(2024, time.July, 23, 8, 0, 0, 0, time.UTC),
+			wantLayout: time.RFC3339Nano,
+		},
+		{
+			name:       "RFC3339",
+			args:       args{date: "2018-03-20T12:49:10Z"},
+			want:       time.Date(2018, time.March, 20, 12, 49, 10, 0, time.UTC),
+			wantLayout: utime.FormatRFC3339,
+		},
+		{
+			name:       "RFC3339 with location",
+			args:       args{date: "2024-07-23T10:00:00Z", options: []utime.Option{utime.WithLocation(madrid)}},
+			want:       time.Date(2024, time.July, 23, 10, 0, 0, 0, time.UTC),
+			wantLayout: utime.FormatRFC3339,
+		},
+		{
+			name:       "ISO date",
+			args:       args{date: "2018-08-18 10:10:10"},
+			want:       time.Date(2018, time.August, 18, 10, 10, 10, 0, time.UTC),
+			wantLayout: utime.FormatISODate,
+		},
+		{
+			name:       "File format",
+			args:       args{date: "2018_03_20_12_49"},
+			want:       time.Date(2018, time.March, 20, 12, 49, 0, 0, time.UTC),
+			wantLayout: utime.FileFormat,
+		},
+		{
+			name:       "Day first",
+			args:       args{date: "03/07/2024"},
+			want:       time.Date(2024, time.July, 3, 0, 0, 0, 0, time.UTC),
+			wantLayout: "2/1/2006",
+		},
+		{
+			name:       "Month first",
+			args:       args{date: "03/07/2024", options: []utime.Option{utime.WithDayFirst(false)}},
+			want:       time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC),
+			wantLayout: "1/2/2006",
+		},
+		{
+			name:       "Month first falls back to day first",
+			args:       args{date: "23/07/2024", options: []utime.Option{utime.WithDayFirst(false)}},
+			want:       time.Date(2024, time.July, 23, 0, 0, 0, 0, time.UTC),
+			wantLayout: "2/1/2006",
+		},
+		{
+			name:       "Month name",
+			args:       args{date: "Jul 23 2024"},
+			want:       time.Date(2024, time.July, 23, 0, 0, 0, 0, time.UTC),
+			wantLayout: "Jan 2 2006",
+		},
+		{
+			name:       "Epoch seconds",
+			args:       args{date: "1721728800"},
+			want:       time.Date(2024, time.July, 23, 10, 0, 0, 0, time.UTC),
+			wantLayout: utime.LayoutUnix,
+		},
+		{
+			name:       "Epoch seconds with fraction",
+			args:       args{date: "1721728800.25"},
+			want:       time.Date(2024, time.July, 23, 10, 0, 0, 250000000, time.UTC),
+			wantLayout: utime.LayoutUnix,
+		},
+		{
+			name:       "Explicit epoch seconds",
+			args:       args{date: "2024", options: []utime.Option{utime.WithLayouts(utime.LayoutUnix)}},
+			want:       time.Date(1970, time.January, 1, 0, 33, 44, 0, time.UTC),
+			wantLayout: utime.LayoutUnix,
+		},
+		{
+			name:       "Location",
+			args:       args{date: "23/07/2024 10:00", options: []utime.Option{utime.WithLocation(madrid)}},
+			want:       time.Date(2024, time.July, 23, 8, 0, 0, 0, time.UTC),
+			wantLayout: "2/1/2006 15:04",
+		},
+		{
+			name:       "Custom layouts",
+			args:       args{date: "20240723", options: []utime.Option{utime.WithLayouts("20060102")}},
+			want:       time.Date(2024, time.July, 23, 0, 0, 0, 0, time.UTC),
+			wantLayout: "20060102",
+		},
+		{
+			name:       "Extra layouts",
+			args:       args{date: "23 jul. 2024", options: []utime.Option{utime.WithExtraLayouts("2 Jan. 2006")}},
+			want:       time.Date(2024, time.July, 23, 0, 0, 0, 0, time.UTC),
+			wantLayout: "2 Jan. 2006",
+		},
+		{
+			name:    "Custom layouts without epoch",
+			args:    args{date: "1721728800", options: []utime.Option{utime.WithLayouts(utime.FormatISODate)}},
+			wantErr: utime.ErrUnknownLayout,
+		},
+		{
+			name:    "Year",
+			args:    args{date: "2024"},
+			wantErr: utime.ErrUnknownLayout,
+		},
+		{
+			name:    "Unknown",
+			args:    args{date: "tomorrow"},
+			wantErr: utime.ErrUnknownLayout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotLayout, err := utime.ParseAny(tt.args.date, tt.args.options...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseAny() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseAny() got = %v, want %v", got, tt.want)
+			}
+			if gotLayout != tt.wantLayout {
+				t.Errorf("ParseAny() gotLayout = %q, want %q", gotLayout, tt.wantLayout)
+			}
+		})
+	}
+}
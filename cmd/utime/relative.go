@@ -0,0 +1,390 @@
+package utime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golibs/cmd/internal/textnorm"
+)
+
+// ErrRelativeDate is matched by every RelativeDateError.
+var ErrRelativeDate = errors.New("time: cannot parse relative date")
+
+// RelativeDateError reports a text ParseRelative cannot read, with the word where the language
+// that read the most of it failed.
+type RelativeDateError struct {
+	Text     string
+	Language string
+	Word     string
+	Msg      string
+}
+
+func (e *RelativeDateError) Error() string {
+	return fmt.Sprintf("time: cannot parse relative date %q in %s: %s", e.Text, e.Language, e.Msg)
+}
+
+func (e *RelativeDateError) Unwrap() error {
+	return ErrRelativeDate
+}
+
+// ParseRelative parses a date relative to reference in the named location, like "tomorrow at
+// 5pm", "next friday", "in 2 weeks", "mañana a las 5" or "hace 3 días", trying the languages in
+// order, English and Spanish when none is given. Case and accents are ignored.
+//
+// A text has at most one date and one time of the day:
+//   - now, a day like tomorrow, or a weekday, the next one from today unless modified by the
+//     words of next or last, which skip today, or of this.
+//   - an amount of a unit with a direction, like in 2 days or 3 hours ago, or a unit modified
+//     by next, last or this, like next week, that move the reference.
+//   - a time like at 5, 17:30, 5:30pm, noon or midnight, without AM or PM in 24 hours.
+//
+// Days and weekdays without time of the day are at midnight, and a time alone is today.
+func ParseRelative(text string, reference time.Time, location string, languages ...*Language) (time.Time, error) {
+	loc, err := LoadLocation(location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(languages) == 0 {
+		languages = []*Language{English, Spanish}
+	}
+
+	words := relativeWords(text)
+	var best *RelativeDateError
+	bestIndex := -1
+	for _, language := range languages {
+		date, index, err := language.parseRelative(words)
+		if err == nil {
+			return date.resolve(reference, loc), nil
+		}
+		if index > bestIndex {
+			err.Text, err.Language = text, language.Name
+			best, bestIndex = err, index
+		}
+	}
+	return time.Time{}, best
+}
+
+// relativeWords return the words of text in lowercase and without accents, splitting the
+// numbers from the letters, as in 5pm.
+func relativeWords(text string) []string {
+	normalized := textnorm.RemoveAccents(strings.ToLower(text))
+
+	var words []string
+	for _, field := range strings.FieldsFunc(normalized, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ':'
+	}) {
+		start, wasLetter := 0, false
+		for i, r := range field {
+			isLetter := unicode.IsLetter(r)
+			if i > 0 && isLetter != wasLetter {
+				words = append(words, field[start:i])
+				start = i
+			}
+			wasLetter = isLetter
+		}
+		words = append(words, field[start:])
+	}
+	return words
+}
+
+type relativeKind int
+
+const (
+	relNone relativeKind = iota
+	relIgnore
+	relNow
+	relDay
+	relWeekday
+	relUnit
+	relNumber
+	relClock
+	relNext
+	relLast
+	relThis
+	relFuture
+	relPast
+	relFutureSuffix
+	relPastSuffix
+	relAt
+	relAM
+	relPM
+	relNoon
+	relMidnight
+)
+
+type relativeToken struct {
+	kind  relativeKind
+	value int
+	word  string
+	index int
+}
+
+// phrases return the tokens of the words of the language, and the words of the longest one.
+func (l *Language) phrases() (map[string]relativeToken, int) {
+	phrases := make(map[string]relativeToken)
+	add := func(kind relativeKind, value int, words ...string) {
+		for _, word := range words {
+			phrases[word] = relativeToken{kind: kind, value: value, word: word}
+		}
+	}
+
+	add(relNow, 0, l.Now...)
+	for word, days := range l.Days {
+		add(relDay, days, word)
+	}
+	for word, weekday := range l.Weekdays {
+		add(relWeekday, int(weekday), word)
+	}
+	for word, unit := range l.Units {
+		add(relUnit, int(unit), word)
+	}
+	for word, n := range l.Numbers {
+		add(relNumber, n, word)
+	}
+	add(relNext, 1, l.Next...)
+	add(relLast, -1, l.Last...)
+	add(relThis, 0, l.This...)
+	add(relFuture, 1, l.Future...)
+	add(relPast, -1, l.Past...)
+	add(relFutureSuffix, 1, l.FutureSuffix...)
+	add(relPastSuffix, -1, l.PastSuffix...)
+	add(relAt, 0, l.At...)
+	add(relAM, 0, l.AM...)
+	add(relPM, 12, l.PM...)
+	add(relNoon, 12*60, l.Noon...)
+	add(relMidnight, 0, l.Midnight...)
+	add(relIgnore, 0, l.Ignore...)
+
+	longest := 0
+	for phrase := range phrases {
+		longest = max(longest, len(strings.Fields(phrase)))
+	}
+	return phrases, longest
+}
+
+// tokens matches the longest phrases of the language, numbers and times like 17:30,
+// returning the index of the word it does not know with the error.
+func (l *Language) tokens(words []string) ([]relativeToken, int, *RelativeDateError) {
+	phrases, longest := l.phrases()
+
+	var tokens []relativeToken
+	for i := 0; i < len(words); {
+		n := min(longest, len(words)-i)
+		for ; n > 0; n-- {
+			if token, ok := phrases[strings.Join(words[i:i+n], " ")]; ok {
+				token.index = i
+				tokens = append(tokens, token)
+				break
+			}
+		}
+		if n > 0 {
+			i += n
+			continue
+		}
+
+		word := words[i]
+		if v, err := strconv.Atoi(word); err == nil {
+			tokens = append(tokens, relativeToken{kind: relNumber, value: v, word: word, index: i})
+		} else if minutes, ok := parseClock(word); ok {
+			tokens = append(tokens, relativeToken{kind: relClock, value: minutes, word: word, index: i})
+		} else {
+			return nil, i, &RelativeDateError{Word: word, Msg: fmt.Sprintf("unknown word %q", word)}
+		}
+		i++
+	}
+	return tokens, len(words), nil
+}
+
+// parseClock parses a time like 17:30 into minutes since midnight.
+func parseClock(word string) (int, bool) {
+	h, m, ok := strings.Cut(word, ":")
+	hour, err := strconv.Atoi(h)
+	if !ok || err != nil || len(m) != 2 {
+		return 0, false
+	}
+	minute, err := strconv.Atoi(m)
+	if err != nil || minute > 59 {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+// relativeDate is the meaning of a text, resolved once it is read.
+type relativeDate struct {
+	date      relativeToken
+	amount    int
+	hasAmount bool
+	modifier  relativeToken
+	clock     relativeToken
+	minutes   int
+}
+
+// parseRelative reads words, returning with the error how far it read: the index of an unknown
+// word, or the number of words plus the index of the one that does not fit, so the languages
+// that know every word are preferred.
+func (l *Language) parseRelative(words []string) (relativeDate, int, *RelativeDateError) {
+	var d relativeDate
+	tokens, index, err := l.tokens(words)
+	if err != nil {
+		return d, index, err
+	}
+
+	at := func(i int) relativeToken {
+		if i < 0 || i >= len(tokens) {
+			return relativeToken{index: len(words)}
+		}
+		return tokens[i]
+	}
+	fail := func(token relativeToken, format string, args ...any) (relativeDate, int, *RelativeDateError) {
+		return d, len(words) + token.index, &RelativeDateError{Word: token.word, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	var direction relativeToken
+	for i := 0; i < len(tokens); i++ {
+		token, next := tokens[i], at(i+1)
+		switch token.kind {
+		case relIgnore:
+		case relNow, relDay, relWeekday, relUnit:
+			// a unit alone, like in next week, moves by the modifier
+			if d.date.kind != relNone {
+				return fail(token, "%q conflicts with %q", token.word, d.date.word)
+			}
+			d.date = token
+		case relNext, relLast, relThis:
+			if d.modifier.kind != relNone {
+				return fail(token, "%q conflicts with %q", token.word, d.modifier.word)
+			}
+			d.modifier = token
+		case relFuture, relPast:
+			if next.kind != relNumber || at(i+2).kind != relUnit {
+				return fail(token, "expected an amount of time after %q", token.word)
+			}
+			direction = token
+		case relNumber:
+			switch {
+			case next.kind == relUnit:
+				if d.date.kind != relNone {
+					return fail(next, "%q conflicts with %q", next.word, d.date.word)
+				}
+				if suffix := at(i + 2); direction.kind == relNone {
+					if suffix.kind != relFutureSuffix && suffix.kind != relPastSuffix {
+						return fail(next, "expected a direction for %s %s", token.word, next.word)
+					}
+					direction = suffix
+					i++
+				}
+				d.date, d.amount, d.hasAmount = next, token.value*direction.value, true
+				direction = relativeToken{}
+				i++
+			case at(i-1).kind == relAt || next.kind == relAM || next.kind == relPM:
+				if i, err = d.setClock(tokens, i, token.value*60); err != nil {
+					return d, len(words) + token.index, err
+				}
+			default:
+				return fail(token, "unexpected number %q", token.word)
+			}
+		case relClock:
+			if i, err = d.setClock(tokens, i, token.value); err != nil {
+				return d, len(words) + token.index, err
+			}
+		case relNoon, relMidnight:
+			if i, err = d.setClock(tokens, i, token.value); err != nil {
+				return d, len(words) + token.index, err
+			}
+		case relAt:
+			if next.kind != relNumber && next.kind != relClock && next.kind != relNoon && next.kind != relMidnight {
+				return fail(token, "expected a time after %q", token.word)
+			}
+		default:
+			return fail(token, "unexpected %q", token.word)
+		}
+	}
+
+	switch {
+	case d.date.kind == relNone && d.clock.kind == relNone:
+		return fail(at(len(tokens)), "no date nor time")
+	case d.modifier.kind != relNone && d.date.kind != relWeekday && (d.date.kind != relUnit || d.hasAmount):
+		return fail(d.modifier, "%q without a weekday or a unit", d.modifier.word)
+	case d.date.kind == relUnit && !d.hasAmount && d.modifier.kind == relNone:
+		return fail(d.date, "expected an amount or next or last for %q", d.date.word)
+	}
+	return d, len(words), nil
+}
+
+// setClock sets the time of the day of the token at i, in minutes since midnight, and of the
+// AM or PM after it, returning the index of the last token read.
+func (d *relativeDate) setClock(tokens []relativeToken, i, minutes int) (int, *RelativeDateError) {
+	token := tokens[i]
+	if d.clock.kind != relNone {
+		return i, &RelativeDateError{Word: token.word, Msg: fmt.Sprintf("%q conflicts with %q", token.word, d.clock.word)}
+	}
+
+	hour := minutes / 60
+	if i+1 < len(tokens) && (tokens[i+1].kind == relAM || tokens[i+1].kind == relPM) {
+		if hour < 1 || hour > 12 {
+			return i, &RelativeDateError{Word: token.word, Msg: fmt.Sprintf("invalid hour %q", token.word)}
+		}
+		i++
+		hour = hour%12 + tokens[i].value
+		minutes = hour*60 + minutes%60
+	}
+	if hour > 23 {
+		return i, &RelativeDateError{Word: token.word, Msg: fmt.Sprintf("invalid hour %q", token.word)}
+	}
+
+	d.clock, d.minutes = token, minutes
+	return i, nil
+}
+
+// resolve return the date relative to reference in loc.
+func (d relativeDate) resolve(reference time.Time, loc *time.Location) time.Time {
+	t := reference.In(loc)
+	day := false
+
+	switch d.date.kind {
+	case relDay:
+		t, day = t.AddDate(0, 0, d.date.value), true
+	case relWeekday:
+		// days to the weekday from today, today included
+		days := (d.date.value - int(t.Weekday()) + 7) % 7
+		switch {
+		case d.modifier.kind == relNext && days == 0:
+			days = 7
+		case d.modifier.kind == relLast:
+			days -= 7
+		}
+		t, day = t.AddDate(0, 0, days), true
+	case relUnit:
+		amount := d.amount
+		if !d.hasAmount {
+			amount = d.modifier.value
+		}
+		switch Unit(d.date.value) {
+		case UnitSecond:
+			t = t.Add(time.Duration(amount) * time.Second)
+		case UnitMinute:
+			t = t.Add(time.Duration(amount) * time.Minute)
+		case UnitHour:
+			t = t.Add(time.Duration(amount) * time.Hour)
+		case UnitDay:
+			t = t.AddDate(0, 0, amount)
+		case UnitWeek:
+			t = t.AddDate(0, 0, 7*amount)
+		case UnitMonth:
+			t = t.AddDate(0, amount, 0)
+		case UnitYear:
+			t = t.AddDate(amount, 0, 0)
+		}
+	}
+
+	if day || d.clock.kind != relNone {
+		y, m, dd := t.Date()
+		wall := time.Date(y, m, dd, 0, 0, 0, 0, time.UTC).Add(time.Duration(d.minutes) * time.Minute)
+		t, _ = localTime(wall, loc, DSTEarliest)
+	}
+	return t
+}
@@ -0,0 +1,135 @@
+package utime_test
+
+import (
+	"errors"
+	"golibs/cmd/utime"
+	"testing"
+	"time"
+)
+
+func TestParseRelative(t *testing.T) {
+	madrid, err := utime.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wednesday
+	reference := time.Date(2024, time.March, 27, 10, 15, 0, 0, madrid)
+
+	tests := []struct {
+		text string
+		want time.Time
+	}{
+		{text: "now", want: reference},
+		{text: "today", want: time.Date(2024, time.March, 27, 0, 0, 0, 0, madrid)},
+		{text: "Tomorrow at 5pm", want: time.Date(2024, time.March, 28, 17, 0, 0, 0, madrid)},
+		{text: "yesterday at noon", want: time.Date(2024, time.March, 26, 12, 0, 0, 0, madrid)},
+		{text: "the day after tomorrow at 9:30 am", want: time.Date(2024, time.March, 29, 9, 30, 0, 0, madrid)},
+		{text: "friday", want: time.Date(2024, time.March, 29, 0, 0, 0, 0, madrid)},
+		{text: "wednesday", want: time.Date(2024, time.March, 27, 0, 0, 0, 0, madrid)},
+		{text: "next Wednesday", want: time.Date(2024, time.April, 3, 0, 0, 0, 0, madrid)},
+		{text: "last friday at 12am", want: time.Date(2024, time.March, 22, 0, 0, 0, 0, madrid)},
+		{text: "in 2 weeks", want: time.Date(2024, time.April, 10, 10, 15, 0, 0, madrid)},
+		{text: "in an hour", want: reference.Add(time.Hour)},
+		{text: "3 days ago", want: time.Date(2024, time.March, 24, 10, 15, 0, 0, madrid)},
+		{text: "a couple of months from now", want: time.Date(2024, time.May, 27, 10, 15, 0, 0, madrid)},
+		{text: "next week", want: time.Date(2024, time.April, 3, 10, 15, 0, 0, madrid)},
+		{text: "at 17:45", want: time.Date(2024, time.March, 27, 17, 45, 0, 0, madrid)},
+		{text: "mañana a las 5", want: time.Date(2024, time.March, 28, 5, 0, 0, 0, madrid)},
+		{text: "mañana a las 5 de la tarde", want: time.Date(2024, time.March, 28, 17, 0, 0, 0, madrid)},
+		{text: "pasado mañana a mediodía", want: time.Date(2024, time.March, 29, 12, 0, 0, 0, madrid)},
+		{text: "hace 3 días", want: time.Date(2024, time.March, 24, 10, 15, 0, 0, madrid)},
+		{text: "dentro de dos horas", want: reference.Add(2 * time.Hour)},
+		{text: "el viernes que viene", want: time.Date(2024, time.March, 29, 0, 0, 0, 0, madrid)},
+		{text: "el próximo miércoles a las 8:15", want: time.Date(2024, time.April, 3, 8, 15, 0, 0, madrid)},
+		{text: "el lunes pasado", want: time.Date(2024, time.March, 25, 0, 0, 0, 0, madrid)},
+		{text: "la semana que viene", want: time.Date(2024, time.April, 3, 10, 15, 0, 0, madrid)},
+		{text: "el mes pasado", want: time.Date(2024, time.February, 27, 10, 15, 0, 0, madrid)},
+		// the clocks go forward on Sunday, March 31
+		{text: "sunday at 2:30", want: time.Date(2024, time.March, 31, 1, 30, 0, 0, time.UTC)},
+		{text: "in 5 days", want: time.Date(2024, time.April, 1, 10, 15, 0, 0, madrid)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := utime.ParseRelative(tt.text, reference, "Europe/Madrid")
+			if err != nil {
+				t.Fatalf("ParseRelative() error = %v", err)
+			}
+			if !got.Equal(tt.want) || got.Location() != madrid {
+				t.Errorf("ParseRelative() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRelative_Errors(t *testing.T) {
+	reference := time.Date(2024, time.March, 27, 10, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		text         string
+		wantLanguage string
+		wantWord     string
+	}{
+		{text: "", wantLanguage: "english"},
+		{text: "the", wantLanguage: "english"},
+		{text: "next blue moon", wantLanguage: "english", wantWord: "blue"},
+		{text: "mañana a las cinco noche", wantLanguage: "spanish", wantWord: "noche"},
+		{text: "tomorrow friday", wantLanguage: "english", wantWord: "friday"},
+		{text: "3 days", wantLanguage: "english", wantWord: "days"},
+		{text: "in 3", wantLanguage: "english", wantWord: "in"},
+		{text: "tomorrow at", wantLanguage: "english", wantWord: "at"},
+		{text: "at 25", wantLanguage: "english", wantWord: "25"},
+		{text: "at 13pm", wantLanguage: "english", wantWord: "13"},
+		{text: "next tomorrow", wantLanguage: "english", wantWord: "next"},
+		{text: "semana", wantLanguage: "spanish", wantWord: "semana"},
+		{text: "hace 2 días atrás", wantLanguage: "spanish", wantWord: "atras"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := utime.ParseRelative(tt.text, reference, "UTC")
+			var relativeErr *utime.RelativeDateError
+			if !errors.As(err, &relativeErr) || !errors.Is(err, utime.ErrRelativeDate) {
+				t.Fatalf("ParseRelative() error = %v, want a RelativeDateError", err)
+			}
+			if relativeErr.Language != tt.wantLanguage || relativeErr.Word != tt.wantWord || relativeErr.Text != tt.text {
+				t.Errorf("ParseRelative() error = %v in %s at %q, want %s at %q", err, relativeErr.Language, relativeErr.Word, tt.wantLanguage, tt.wantWord)
+			}
+		})
+	}
+
+	if _, err := utime.ParseRelative("now", reference, "Mars/Olympus"); !errors.Is(err, utime.ErrUnknownZone) {
+		t.Errorf("ParseRelative() error = %v, want ErrUnknownZone", err)
+	}
+}
+
+func TestParseRelative_Language(t *testing.T) {
+	reference := time.Date(2024, time.March, 27, 10, 15, 0, 0, time.UTC)
+	french := &utime.Language{
+		Name:     "french",
+		Days:     map[string]int{"aujourd hui": 0, "demain": 1, "apres demain": 2},
+		Units:    map[string]utime.Unit{"jour": utime.UnitDay, "jours": utime.UnitDay},
+		Numbers:  map[string]int{"un": 1, "deux": 2},
+		Future:   []string{"dans"},
+		Past:     []string{"il y a"},
+		At:       []string{"a"},
+		Midnight: []string{"minuit"},
+	}
+
+	tests := []struct {
+		text string
+		want time.Time
+	}{
+		{text: "après-demain à 18:00", want: time.Date(2024, time.March, 29, 18, 0, 0, 0, time.UTC)},
+		{text: "il y a deux jours", want: time.Date(2024, time.March, 25, 10, 15, 0, 0, time.UTC)},
+		{text: "dans un jour", want: time.Date(2024, time.March, 28, 10, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := utime.ParseRelative(tt.text, reference, "UTC", french)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseRelative(%q) = %v, %v, want %v", tt.text, got, err, tt.want)
+		}
+	}
+
+	if _, err := utime.ParseRelative("tomorrow", reference, "UTC", french); !errors.Is(err, utime.ErrRelativeDate) {
+		t.Errorf("ParseRelative() error = %v, want ErrRelativeDate", err)
+	}
+}
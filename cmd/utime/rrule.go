@@ -0,0 +1,629 @@
+package utime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule is returned for the recurrence rules that cannot be parsed.
+var ErrInvalidRule = errors.New("time: invalid recurrence rule")
+
+// Layouts of the iCalendar dates.
+const (
+	icalDate  = "20060102"
+	icalLocal = "20060102T150405"
+	icalUTC   = "20060102T150405Z"
+)
+
+// ruleGiveUpYears is how long an iterator looks for an occurrence after the last one, so the
+// rules that never match, like February 30, end instead of looping forever.
+const ruleGiveUpYears = 400
+
+// Frequency is the FREQ of a recurrence rule.
+type Frequency int
+
+const (
+	FreqDaily Frequency = iota
+	FreqWeekly
+	FreqMonthly
+	FreqYearly
+)
+
+var frequencies = []string{
+	FreqDaily:   "DAILY",
+	FreqWeekly:  "WEEKLY",
+	FreqMonthly: "MONTHLY",
+	FreqYearly:  "YEARLY",
+}
+
+func (f Frequency) String() string {
+	if f < 0 || int(f) >= len(frequencies) {
+		return "Frequency(" + strconv.Itoa(int(f)) + ")"
+	}
+	return frequencies[f]
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RuleWeekday is a BYDAY value: a weekday or, when N is not 0, its Nth occurrence in the month
+// or the year, counting from the end when N is negative.
+type RuleWeekday struct {
+	Weekday time.Weekday
+	N       int
+}
+
+func (w RuleWeekday) String() string {
+	if w.N == 0 {
+		return weekdayCodes[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + weekdayCodes[w.Weekday]
+}
+
+// Rule is an RFC 5545 recurrence rule, with its start, DTSTART, and its excluded dates, EXDATE.
+//
+// Each period of the frequency contributes the days matching the BY parts, at the wall clock
+// time of Start in its location, so the occurrences keep their local time across DST changes
+// and the ones falling in a gap move forward by its length. Without BYDAY and BYMONTHDAY the
+// weekly, monthly and yearly rules repeat the weekday, the day of the month or the date of
+// Start. Start is the first occurrence only when it matches the rule, and the excluded dates
+// still count for Count.
+type Rule struct {
+	Freq Frequency
+	// Interval is the number of periods between occurrences, 0 is 1.
+	Interval int
+	// Count and Until end the rule after a number of occurrences or at an instant, included.
+	// Zero values do not end it.
+	Count int
+	Until time.Time
+
+	ByMonth    []time.Month
+	ByMonthDay []int
+	ByDay      []RuleWeekday
+	BySetPos   []int
+	// WeekStart is WKST, the first day of the weekly periods. ParseRule defaults it to Monday.
+	WeekStart time.Weekday
+
+	Start   time.Time
+	ExDates []time.Time
+}
+
+// ParseRule parses the value of an RRULE property, with or without the RRULE: name, like
+// FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1. The rule starts at start, whose location is
+// also the one of the occurrences and of an UNTIL without time zone. An UNTIL date without
+// time ends the rule at the end of that day.
+func ParseRule(rule string, start time.Time) (*Rule, error) {
+	r := &Rule{Interval: 1, WeekStart: time.Monday, Start: start}
+	if start.IsZero() {
+		return nil, fmt.Errorf("%w: missing start", ErrInvalidRule)
+	}
+
+	value := strings.TrimSpace(rule)
+	if len(value) >= 6 && strings.EqualFold(value[:6], "RRULE:") {
+		value = value[6:]
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ";") {
+		name, v, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if !ok || v == "" {
+			return nil, fmt.Errorf("%w: invalid part %q", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: repeated %s", ErrInvalidRule, name)
+		}
+		seen[name] = true
+		if err := r.setPart(name, strings.ToUpper(strings.TrimSpace(v))); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRule, name, err)
+		}
+	}
+
+	if !seen["FREQ"] {
+		return nil, fmt.Errorf("%w: missing FREQ", ErrInvalidRule)
+	}
+	if err := r.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	return r, nil
+}
+
+// ParseRecurrence parses the lines of a DTSTART, an RRULE and optionally EXDATE properties,
+// like
+//
+//	DTSTART;TZID=Europe/Madrid:20240105T090000
+//	RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=FR
+//	EXDATE;TZID=Europe/Madrid:20240202T090000,20240216T090000
+//
+// The dates without TZID nor Z are in the named location, empty for UTC.
+func ParseRecurrence(text, location string) (*Rule, error) {
+	loc, err := LoadLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
+	var start []time.Time
+	var rule string
+	var exDates [][2]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		property, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid line %q", ErrInvalidRule, line)
+		}
+		name, params, _ := strings.Cut(property, ";")
+
+		switch strings.ToUpper(name) {
+		case "DTSTART":
+			if start != nil {
+				return nil, fmt.Errorf("%w: repeated DTSTART", ErrInvalidRule)
+			}
+			if start, err = parseICalTimes(params, value, loc); err != nil {
+				return nil, err
+			}
+			if len(start) != 1 {
+				return nil, fmt.Errorf("%w: invalid DTSTART %q", ErrInvalidRule, value)
+			}
+		case "RRULE":
+			if rule != "" {
+				return nil, fmt.Errorf("%w: repeated RRULE", ErrInvalidRule)
+			}
+			rule = value
+		case "EXDATE":
+			exDates = append(exDates, [2]string{params, value})
+		default:
+			return nil, fmt.Errorf("%w: unknown property %q", ErrInvalidRule, name)
+		}
+	}
+	if start == nil || rule == "" {
+		return nil, fmt.Errorf("%w: missing DTSTART or RRULE", ErrInvalidRule)
+	}
+
+	r, err := ParseRule(rule, start[0])
+	if err != nil {
+		return nil, err
+	}
+	// the floating excluded dates are in the location of the start
+	for _, exDate := range exDates {
+		times, err := parseICalTimes(exDate[0], exDate[1], start[0].Location())
+		if err != nil {
+			return nil, err
+		}
+		r.ExDates = append(r.ExDates, times...)
+	}
+	return r, nil
+}
+
+func (r *Rule) setPart(name, value string) error {
+	var err error
+	switch name {
+	case "FREQ":
+		for f, code := range frequencies {
+			if code == value {
+				r.Freq = Frequency(f)
+				return nil
+			}
+		}
+		return fmt.Errorf("unsupported frequency %q", value)
+	case "INTERVAL":
+		r.Interval, err = strconv.Atoi(value)
+		if err != nil || r.Interval < 1 {
+			return fmt.Errorf("invalid interval %q", value)
+		}
+	case "COUNT":
+		r.Count, err = strconv.Atoi(value)
+		if err != nil || r.Count < 1 {
+			return fmt.Errorf("invalid count %q", value)
+		}
+	case "UNTIL":
+		r.Until, err = parseICalTime(value, r.Start.Location())
+		if err != nil {
+			return err
+		}
+		if len(value) == len(icalDate) {
+			r.Until = r.Until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	case "BYMONTH":
+		months, err := parseInts(value, 1, 12, false)
+		if err != nil {
+			return err
+		}
+		for _, m := range months {
+			r.ByMonth = append(r.ByMonth, time.Month(m))
+		}
+	case "BYMONTHDAY":
+		r.ByMonthDay, err = parseInts(value, 1, 31, true)
+		return err
+	case "BYSETPOS":
+		r.BySetPos, err = parseInts(value, 1, 366, true)
+		return err
+	case "BYDAY":
+		for _, v := range strings.Split(value, ",") {
+			if len(v) < 2 {
+				return fmt.Errorf("invalid weekday %q", v)
+			}
+			weekday, ok := parseWeekdayCode(v[len(v)-2:])
+			if !ok {
+				return fmt.Errorf("invalid weekday %q", v)
+			}
+			day := RuleWeekday{Weekday: weekday}
+			if n := v[:len(v)-2]; n != "" {
+				day.N, err = strconv.Atoi(n)
+				if err != nil || day.N == 0 || day.N < -53 || day.N > 53 {
+					return fmt.Errorf("invalid weekday %q", v)
+				}
+			}
+			r.ByDay = append(r.ByDay, day)
+		}
+	case "WKST":
+		weekday, ok := parseWeekdayCode(value)
+		if !ok {
+			return fmt.Errorf("invalid weekday %q", value)
+		}
+		r.WeekStart = weekday
+	default:
+		return errors.New("unsupported part")
+	}
+	return nil
+}
+
+// validate rejects the combinations of parts RFC 5545 forbids.
+func (r *Rule) validate() error {
+	if r.Count > 0 && !r.Until.IsZero() {
+		return errors.New("COUNT and UNTIL are exclusive")
+	}
+	if r.Freq == FreqWeekly && len(r.ByMonthDay) > 0 {
+		return errors.New("BYMONTHDAY with a weekly frequency")
+	}
+	for _, day := range r.ByDay {
+		switch {
+		case day.N == 0:
+		case r.Freq != FreqMonthly && r.Freq != FreqYearly:
+			return fmt.Errorf("BYDAY %v with a %v frequency", day, r.Freq)
+		case (r.Freq == FreqMonthly || len(r.ByMonth) > 0) && (day.N < -5 || day.N > 5):
+			return fmt.Errorf("BYDAY %v out of the month", day)
+		}
+	}
+	return nil
+}
+
+func parseInts(value string, low, high int, negative bool) ([]int, error) {
+	var values []int
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil || n == 0 || n > high || n < low && !(negative && n >= -high) {
+			return nil, fmt.Errorf("invalid value %q", v)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func parseWeekdayCode(code string) (time.Weekday, bool) {
+	for day, c := range weekdayCodes {
+		if c == code {
+			return time.Weekday(day), true
+		}
+	}
+	return 0, false
+}
+
+// parseICalTimes parses the comma separated dates of a property, in the location of its
+// TZID parameter or in loc.
+func parseICalTimes(params, value string, loc *time.Location) ([]time.Time, error) {
+	for _, param := range strings.Split(params, ";") {
+		if name, tzid, ok := strings.Cut(param, "="); ok && strings.EqualFold(name, "TZID") {
+			var err error
+			if loc, err = LoadLocation(tzid); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var times []time.Time
+	for _, v := range strings.Split(value, ",") {
+		t, err := parseICalTime(v, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// parseICalTime parses a date, a UTC date time or a local one in loc.
+func parseICalTime(value string, loc *time.Location) (time.Time, error) {
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(icalUTC, value)
+	}
+	layout := icalLocal
+	if len(value) == len(icalDate) {
+		layout = icalDate
+	}
+	wall, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return localTime(wall, loc, DSTEarliest)
+}
+
+// String return the value of the RRULE property of the rule.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCodes[r.WeekStart])
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinValues(r.ByMonth, func(m time.Month) string { return strconv.Itoa(int(m)) }))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinValues(r.ByMonthDay, strconv.Itoa))
+	}
+	if len(r.ByDay) > 0 {
+		parts = append(parts, "BYDAY="+joinValues(r.ByDay, RuleWeekday.String))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinValues(r.BySetPos, strconv.Itoa))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(icalUTC))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Recurrence return the DTSTART, RRULE and EXDATE lines of the rule, as read by
+// ParseRecurrence.
+func (r *Rule) Recurrence() string {
+	lines := []string{icalProperty("DTSTART", r.Start.Location(), r.Start), "RRULE:" + r.String()}
+	if len(r.ExDates) > 0 {
+		lines = append(lines, icalProperty("EXDATE", r.Start.Location(), r.ExDates...))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func icalProperty(name string, loc *time.Location, times ...time.Time) string {
+	utc := loc.String() == "UTC"
+	values := joinValues(times, func(t time.Time) string {
+		if utc {
+			return t.UTC().Format(icalUTC)
+		}
+		return t.In(loc).Format(icalLocal)
+	})
+	if !utc {
+		name += ";TZID=" + loc.String()
+	}
+	return name + ":" + values
+}
+
+func joinValues[T any](values []T, format func(T) string) string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = format(v)
+	}
+	return strings.Join(formatted, ",")
+}
+
+// Between return the occurrences from from to to, both included.
+func (r *Rule) Between(from, to time.Time) []time.Time {
+	var times []time.Time
+	it := r.Iterator()
+	for t, ok := it.Next(); ok && !t.After(to); t, ok = it.Next() {
+		if !t.Before(from) {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+// Iterator return an iterator over the occurrences of the rule, from its start.
+func (r *Rule) Iterator() *RuleIterator {
+	excluded := make(map[time.Time]bool, len(r.ExDates))
+	for _, t := range r.ExDates {
+		excluded[t.UTC()] = true
+	}
+	return &RuleIterator{rule: r, excluded: excluded, lastYear: r.Start.Year()}
+}
+
+// RuleIterator iterates over the occurrences of a Rule in order. The rule must not change
+// while iterating.
+type RuleIterator struct {
+	rule     *Rule
+	excluded map[time.Time]bool
+
+	period   int
+	pending  []time.Time
+	count    int
+	lastYear int
+	done     bool
+}
+
+// Next return the next occurrence, or false when the rule has ended.
+func (it *RuleIterator) Next() (time.Time, bool) {
+	for {
+		if len(it.pending) == 0 {
+			if it.done {
+				return time.Time{}, false
+			}
+			it.expand()
+			continue
+		}
+
+		t := it.pending[0]
+		it.pending = it.pending[1:]
+		if !it.excluded[t.UTC()] {
+			return t, true
+		}
+	}
+}
+
+// expand queues the occurrences of the next period.
+func (it *RuleIterator) expand() {
+	r := it.rule
+	start := r.period(it.period)
+	it.period++
+	if start.Year() > it.lastYear+ruleGiveUpYears {
+		it.done = true
+		return
+	}
+
+	for _, t := range r.occurrences(start) {
+		if t.Before(r.Start) {
+			continue
+		}
+		if !r.Until.IsZero() && t.After(r.Until) {
+			it.done = true
+			break
+		}
+		it.pending = append(it.pending, t)
+		it.lastYear = start.Year()
+		if it.count++; r.Count > 0 && it.count >= r.Count {
+			it.done = true
+			break
+		}
+	}
+}
+
+// period return the first day of the nth period of the rule, as a civil date.
+func (r *Rule) period(n int) time.Time {
+	y, m, d := r.Start.Date()
+	n *= max(r.Interval, 1)
+	switch r.Freq {
+	case FreqYearly:
+		return civilDate(y+n, time.January, 1)
+	case FreqMonthly:
+		return civilDate(y, m+time.Month(n), 1)
+	case FreqWeekly:
+		back := (int(r.Start.Weekday()) - int(r.WeekStart) + 7) % 7
+		return civilDate(y, m, d-back+7*n)
+	}
+	return civilDate(y, m, d+n)
+}
+
+// occurrences return the occurrences in the period starting at the civil date start.
+func (r *Rule) occurrences(start time.Time) []time.Time {
+	var end time.Time
+	switch r.Freq {
+	case FreqYearly:
+		end = start.AddDate(1, 0, 0)
+	case FreqMonthly:
+		end = start.AddDate(0, 1, 0)
+	case FreqWeekly:
+		end = start.AddDate(0, 0, 7)
+	default:
+		end = start.AddDate(0, 0, 1)
+	}
+
+	byMonth, byMonthDay, byDay := r.ByMonth, r.ByMonthDay, r.ByDay
+	if len(byDay) == 0 && len(byMonthDay) == 0 {
+		switch r.Freq {
+		case FreqWeekly:
+			byDay = []RuleWeekday{{Weekday: r.Start.Weekday()}}
+		case FreqMonthly:
+			byMonthDay = []int{r.Start.Day()}
+		case FreqYearly:
+			byMonthDay = []int{r.Start.Day()}
+			if len(byMonth) == 0 {
+				byMonth = []time.Month{r.Start.Month()}
+			}
+		}
+	}
+	// the ordinals of BYDAY count in the year only for yearly rules without BYMONTH
+	inYear := r.Freq == FreqYearly && len(r.ByMonth) == 0
+
+	var days []time.Time
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if matchMonth(day, byMonth) && matchMonthDay(day, byMonthDay) && matchDay(day, byDay, inYear) {
+			days = append(days, day)
+		}
+	}
+	if len(r.BySetPos) > 0 {
+		days = selectPositions(days, r.BySetPos)
+	}
+
+	times := make([]time.Time, len(days))
+	h, m, s := r.Start.Clock()
+	for i, day := range days {
+		wall := time.Date(day.Year(), day.Month(), day.Day(), h, m, s, r.Start.Nanosecond(), time.UTC)
+		times[i], _ = localTime(wall, r.Start.Location(), DSTEarliest)
+	}
+	return times
+}
+
+func matchMonth(day time.Time, months []time.Month) bool {
+	if len(months) == 0 {
+		return true
+	}
+	for _, m := range months {
+		if day.Month() == m {
+			return true
+		}
+	}
+	return false
+}
+
+func matchMonthDay(day time.Time, monthDays []int) bool {
+	if len(monthDays) == 0 {
+		return true
+	}
+	last := civilDate(day.Year(), day.Month()+1, 0).Day()
+	for _, d := range monthDays {
+		if d == day.Day() || d == day.Day()-last-1 {
+			return true
+		}
+	}
+	return false
+}
+
+func matchDay(day time.Time, weekdays []RuleWeekday, inYear bool) bool {
+	if len(weekdays) == 0 {
+		return true
+	}
+
+	index, last := day.Day(), civilDate(day.Year(), day.Month()+1, 0).Day()
+	if inYear {
+		index, last = day.YearDay(), civilDate(day.Year(), time.December, 31).YearDay()
+	}
+	for _, w := range weekdays {
+		switch {
+		case w.Weekday != day.Weekday():
+		case w.N == 0, w.N == (index-1)/7+1, w.N == -((last-index)/7 + 1):
+			return true
+		}
+	}
+	return false
+}
+
+// selectPositions return the days at the positions of BYSETPOS, in order.
+func selectPositions(days []time.Time, positions []int) []time.Time {
+	var indexes []int
+	for _, p := range positions {
+		i := p - 1
+		if p < 0 {
+			i = len(days) + p
+		}
+		if i >= 0 && i < len(days) {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Ints(indexes)
+
+	var selected []time.Time
+	for i, index := range indexes {
+		if i == 0 || index != indexes[i-1] {
+			selected = append(selected, days[index])
+		}
+	}
+	return selected
+}
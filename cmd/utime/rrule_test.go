@@ -0,0 +1,261 @@
+package utime_test
+
+import (
+	"errors"
+	"golibs/cmd/utime"
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	madrid, err := utime.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := utime.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "Weekdays",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+			start: time.Date(2024, time.January, 1, 9, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.January, 1, 9, 0, 0, 0, madrid),
+				time.Date(2024, time.January, 3, 9, 0, 0, 0, madrid),
+				time.Date(2024, time.January, 8, 9, 0, 0, 0, madrid),
+				time.Date(2024, time.January, 10, 9, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name:  "Week start",
+			rule:  "RRULE:FREQ=WEEKLY;INTERVAL=2;WKST=SU;BYDAY=TU,SU;COUNT=4",
+			start: time.Date(1997, time.August, 5, 9, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(1997, time.August, 5, 9, 0, 0, 0, newYork),
+				time.Date(1997, time.August, 17, 9, 0, 0, 0, newYork),
+				time.Date(1997, time.August, 19, 9, 0, 0, 0, newYork),
+				time.Date(1997, time.August, 31, 9, 0, 0, 0, newYork),
+			},
+		},
+		{
+			name:  "Last working day of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=3",
+			start: time.Date(2024, time.January, 1, 18, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.January, 31, 18, 0, 0, 0, madrid),
+				time.Date(2024, time.February, 29, 18, 0, 0, 0, madrid),
+				time.Date(2024, time.March, 29, 18, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name:  "Last day of the month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			start: time.Date(2024, time.January, 15, 12, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.January, 31, 12, 0, 0, 0, madrid),
+				time.Date(2024, time.February, 29, 12, 0, 0, 0, madrid),
+				time.Date(2024, time.March, 31, 12, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name:  "Friday 13th",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=13;BYDAY=FR;UNTIL=20250101T000000Z",
+			start: time.Date(2024, time.January, 1, 0, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.September, 13, 0, 0, 0, 0, madrid),
+				time.Date(2024, time.December, 13, 0, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name:  "Thanksgiving",
+			rule:  "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;COUNT=2",
+			start: time.Date(2024, time.January, 1, 12, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2024, time.November, 28, 12, 0, 0, 0, newYork),
+				time.Date(2025, time.November, 27, 12, 0, 0, 0, newYork),
+			},
+		},
+		{
+			name:  "Last Monday of the year",
+			rule:  "FREQ=YEARLY;BYDAY=-1MO;UNTIL=20251231",
+			start: time.Date(2024, time.January, 1, 12, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.December, 30, 12, 0, 0, 0, madrid),
+				time.Date(2025, time.December, 29, 12, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name:  "Leap day",
+			rule:  "FREQ=YEARLY;COUNT=2",
+			start: time.Date(2024, time.February, 29, 8, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.February, 29, 8, 0, 0, 0, madrid),
+				time.Date(2028, time.February, 29, 8, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name:  "Interval",
+			rule:  "FREQ=DAILY;INTERVAL=10;COUNT=3",
+			start: time.Date(2024, time.January, 30, 8, 0, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.January, 30, 8, 0, 0, 0, madrid),
+				time.Date(2024, time.February, 9, 8, 0, 0, 0, madrid),
+				time.Date(2024, time.February, 19, 8, 0, 0, 0, madrid),
+			},
+		},
+		{
+			name:  "Skipped by the clocks going forward",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: time.Date(2024, time.March, 30, 2, 30, 0, 0, madrid),
+			want: []time.Time{
+				time.Date(2024, time.March, 30, 1, 30, 0, 0, time.UTC),
+				time.Date(2024, time.March, 31, 1, 30, 0, 0, time.UTC),
+				time.Date(2024, time.April, 1, 0, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "Never",
+			rule:  "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			start: time.Date(2024, time.January, 1, 0, 0, 0, 0, madrid),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := utime.ParseRule(tt.rule, tt.start)
+			if err != nil {
+				t.Fatalf("ParseRule() error = %v", err)
+			}
+
+			var got []time.Time
+			it := rule.Iterator()
+			for next, ok := it.Next(); ok; next, ok = it.Next() {
+				got = append(got, next)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("occurrences = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) || got[i].Location() != tt.start.Location() {
+					t.Errorf("occurrence %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+
+			again, err := utime.ParseRule(rule.String(), tt.start)
+			if err != nil || again.String() != rule.String() {
+				t.Errorf("ParseRule(String()) = %v, %v, want %v", again, err, rule)
+			}
+		})
+	}
+}
+
+func TestRule_Between(t *testing.T) {
+	madrid, err := utime.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, err := utime.ParseRule("FREQ=DAILY", time.Date(2024, time.January, 1, 9, 0, 0, 0, madrid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule.ExDates = []time.Time{time.Date(2024, time.June, 11, 7, 0, 0, 0, time.UTC)}
+
+	got := rule.Between(time.Date(2024, time.June, 10, 9, 0, 0, 0, madrid), time.Date(2024, time.June, 12, 9, 0, 0, 0, madrid))
+	want := []time.Time{
+		time.Date(2024, time.June, 10, 9, 0, 0, 0, madrid),
+		time.Date(2024, time.June, 12, 9, 0, 0, 0, madrid),
+	}
+	if len(got) != len(want) || !got[0].Equal(want[0]) || !got[1].Equal(want[1]) {
+		t.Errorf("Between() = %v, want %v", got, want)
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	text := "DTSTART;TZID=Europe/Madrid:20240105T090000\n" +
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=FR;UNTIL=20240301T080000Z\n" +
+		"EXDATE;TZID=Europe/Madrid:20240202T090000"
+	rule, err := utime.ParseRecurrence(text, "")
+	if err != nil {
+		t.Fatalf("ParseRecurrence() error = %v", err)
+	}
+	if got := rule.Recurrence(); got != text {
+		t.Errorf("Recurrence() = %q, want %q", got, text)
+	}
+
+	madrid := rule.Start.Location()
+	got := rule.Between(rule.Start, rule.Until)
+	want := []time.Time{
+		time.Date(2024, time.January, 5, 9, 0, 0, 0, madrid),
+		time.Date(2024, time.January, 19, 9, 0, 0, 0, madrid),
+		time.Date(2024, time.February, 16, 9, 0, 0, 0, madrid),
+		time.Date(2024, time.March, 1, 9, 0, 0, 0, madrid),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Between() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("Between()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// floating dates are in the location
+	rule, err = utime.ParseRecurrence("DTSTART:20240105T090000\nRRULE:FREQ=DAILY;COUNT=2", "America/New_York")
+	if err != nil {
+		t.Fatalf("ParseRecurrence() error = %v", err)
+	}
+	if want := time.Date(2024, time.January, 5, 14, 0, 0, 0, time.UTC); !rule.Start.Equal(want) {
+		t.Errorf("Start = %v, want %v", rule.Start, want)
+	}
+
+	rule, err = utime.ParseRecurrence("DTSTART:20240105T090000Z\nRRULE:FREQ=DAILY;COUNT=2", "America/New_York")
+	if err != nil {
+		t.Fatalf("ParseRecurrence() error = %v", err)
+	}
+	if got, want := rule.Recurrence(), "DTSTART:20240105T090000Z\nRRULE:FREQ=DAILY;COUNT=2"; got != want {
+		t.Errorf("Recurrence() = %q, want %q", got, want)
+	}
+}
+
+func TestParseRule_Errors(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=DAILY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240201",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=YEARLY;BYMONTH=-1",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+	}
+	for _, rule := range invalid {
+		if _, err := utime.ParseRule(rule, start); !errors.Is(err, utime.ErrInvalidRule) {
+			t.Errorf("ParseRule(%q) error = %v, want ErrInvalidRule", rule, err)
+		}
+	}
+
+	if _, err := utime.ParseRule("FREQ=DAILY", time.Time{}); !errors.Is(err, utime.ErrInvalidRule) {
+		t.Errorf("ParseRule() without start error = %v, want ErrInvalidRule", err)
+	}
+	if _, err := utime.ParseRecurrence("RRULE:FREQ=DAILY", ""); !errors.Is(err, utime.ErrInvalidRule) {
+		t.Errorf("ParseRecurrence() without DTSTART error = %v, want ErrInvalidRule", err)
+	}
+	if _, err := utime.ParseRecurrence("DTSTART;TZID=Mars/Olympus:20240101T090000\nRRULE:FREQ=DAILY", ""); !errors.Is(err, utime.ErrUnknownZone) {
+		t.Errorf("ParseRecurrence() error = %v, want ErrUnknownZone", err)
+	}
+}
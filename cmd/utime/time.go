@@ -2,10 +2,11 @@ package utime
 
 import (
 	"errors"
-	"strings"
 	"time"
 )
 
+// ErrLocation matches, with errors.Is, the errors of the location names that cannot be loaded
+// because they are unknown or invalid.
 var ErrLocation = errors.New("time: invalid location name")
 
 const (
@@ -38,11 +39,8 @@ func FormatDateToFileNameFormat(date time.Time) string {
 // Format format date to string with specific format and location.
 func Format(date time.Time, format string, location string) (dateString string, err error) {
 	if !date.IsZero() {
-		location, err := time.LoadLocation(location)
+		location, err := LoadLocation(location)
 		if err != nil {
-			if strings.Contains(err.Error(), "unknown time zone") {
-				return "", ErrLocation
-			}
 			return "", err
 		}
 		dateString = date.UTC().In(location).Format(format)
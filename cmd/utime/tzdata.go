@@ -0,0 +1,7 @@
+//go:build utime_tzdata
+
+package utime
+
+// Building with -tags utime_tzdata embeds the time zone database, about 450KB, for systems
+// without /usr/share/zoneinfo. It is used when the system one is not found.
+import _ "time/tzdata"
@@ -0,0 +1,70 @@
+package utime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Causes of a LocationError.
+var (
+	ErrUnknownZone     = errors.New("time: unknown time zone")
+	ErrInvalidZoneName = errors.New("time: invalid time zone name")
+	ErrZoneDatabase    = errors.New("time: time zone database not found, build with -tags utime_tzdata to embed it")
+)
+
+// LocationError is returned when a location cannot be loaded. Err is ErrUnknownZone,
+// ErrInvalidZoneName or ErrZoneDatabase, the first two also matching ErrLocation, and Cause
+// the error of time.LoadLocation, if any.
+type LocationError struct {
+	Name  string
+	Err   error
+	Cause error
+}
+
+func (e *LocationError) Error() string {
+	return fmt.Sprintf("%v: %q", e.Err, e.Name)
+}
+
+func (e *LocationError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrLocation for unknown or invalid names, so the errors of
+// Format keep matching it.
+func (e *LocationError) Is(target error) bool {
+	return target == ErrLocation && (e.Err == ErrUnknownZone || e.Err == ErrInvalidZoneName)
+}
+
+// referenceZone is loaded to tell an unknown zone from a missing database.
+const referenceZone = "Etc/GMT"
+
+var locations sync.Map // string -> *time.Location
+
+// LoadLocation is time.LoadLocation keeping the loaded locations in memory, so Format and the
+// parsing functions only read the time zone database once per name. Its errors are
+// LocationError.
+func LoadLocation(name string) (*time.Location, error) {
+	if location, ok := locations.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+
+	if strings.Contains(name, "..") || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) {
+		return nil, &LocationError{Name: name, Err: ErrInvalidZoneName}
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		if _, refErr := time.LoadLocation(referenceZone); refErr != nil {
+			return nil, &LocationError{Name: name, Err: ErrZoneDatabase, Cause: err}
+		}
+		// with the database found, a name that cannot be loaded is missing from it or has
+		// unreadable data
+		return nil, &LocationError{Name: name, Err: ErrUnknownZone, Cause: err}
+	}
+
+	actual, _ := locations.LoadOrStore(name, location)
+	return actual.(*time.Location), nil
+}
@@ -0,0 +1,75 @@
+package utime_test
+
+import (
+	"errors"
+	"golibs/cmd/utime"
+	"testing"
+	"time"
+)
+
+func TestLoadLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		zone    string
+		wantErr error
+	}{
+		{name: "Known", zone: "America/Argentina/Buenos_Aires"},
+		{name: "UTC", zone: "UTC"},
+		{name: "Unknown", zone: "America/Argentina/Buenos_Air", wantErr: utime.ErrUnknownZone},
+		{name: "Invalid", zone: "../etc/passwd", wantErr: utime.ErrInvalidZoneName},
+		{name: "Absolute", zone: "/usr/share/zoneinfo/UTC", wantErr: utime.ErrInvalidZoneName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := utime.LoadLocation(tt.zone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadLocation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				var locErr *utime.LocationError
+				if !errors.As(err, &locErr) || locErr.Name != tt.zone {
+					t.Errorf("LoadLocation() error = %#v, want a LocationError for %q", err, tt.zone)
+				}
+				if !errors.Is(err, utime.ErrLocation) {
+					t.Errorf("LoadLocation() error = %v, want it to match ErrLocation", err)
+				}
+				if tt.wantErr == utime.ErrUnknownZone && locErr.Cause == nil {
+					t.Errorf("LoadLocation() error = %#v, want the error of time.LoadLocation as cause", err)
+				}
+				return
+			}
+			if got.String() != tt.zone {
+				t.Errorf("LoadLocation() = %v, want %v", got, tt.zone)
+			}
+		})
+	}
+}
+
+func TestLoadLocation_Cached(t *testing.T) {
+	first, err := utime.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := utime.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("LoadLocation() returned different locations for the same name")
+	}
+}
+
+func TestFormat_LocationError(t *testing.T) {
+	_, err := utime.Format(time.Date(2018, time.March, 20, 15, 49, 10, 0, time.UTC), utime.FileFormat, "Mars/Olympus")
+	var locErr *utime.LocationError
+	if !errors.As(err, &locErr) || !errors.Is(err, utime.ErrUnknownZone) || !errors.Is(err, utime.ErrLocation) {
+		t.Errorf("Format() error = %v, want an unknown zone LocationError", err)
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	date := time.Date(2018, time.March, 20, 15, 49, 10, 0, time.UTC)
+	for i := 0; i < b.N; i++ {
+		_, _ = utime.Format(date, utime.FormatISO8601, "America/Argentina/Buenos_Aires")
+	}
+}